@@ -8,3 +8,19 @@ require (
 )
 
 require github.com/a-h/templ v0.3.943
+
+require golang.org/x/crypto v0.40.0
+
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/jung-kurt/gofpdf v1.16.2
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	golang.org/x/image v0.25.0
+	golang.org/x/term v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sys v0.35.0
+	golang.org/x/text v0.27.0 // indirect
+)
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sessionCookie = "sb_session"
+
+// DefaultSessionTTL is how long a sign-in lasts.
+const DefaultSessionTTL = 12 * time.Hour
+
+type ctxKey int
+
+const userKey ctxKey = iota
+
+type session struct {
+	username string
+	expires  time.Time
+}
+
+// Manager ties the user store to cookie sessions and gates routes by role.
+// Sessions live in memory, so a restart signs everyone out.
+type Manager struct {
+	Users  *Users
+	Tokens *TeamTokens
+	// SessionTTL is how long a sign-in lasts; set it before serving requests.
+	SessionTTL time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]session
+}
+
+// NewManager creates a Manager bound to the user and team token stores.
+func NewManager(users *Users, tokens *TeamTokens) *Manager {
+	return &Manager{
+		Users:      users,
+		Tokens:     tokens,
+		SessionTTL: DefaultSessionTTL,
+		sessions:   make(map[string]session),
+	}
+}
+
+// newToken returns a random URL-safe token.
+func newToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Login starts a session for the user and sets the session cookie.
+func (m *Manager) Login(w http.ResponseWriter, r *http.Request, u *User) {
+	token := newToken()
+	expires := time.Now().Add(m.SessionTTL)
+
+	m.mu.Lock()
+	// Drop stale sessions while we're here so the map doesn't grow forever.
+	now := time.Now()
+	for t, s := range m.sessions {
+		if now.After(s.expires) {
+			delete(m.sessions, t)
+		}
+	}
+	m.sessions[token] = session{username: u.Username, expires: expires}
+	m.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Logout ends the current session and clears the cookie.
+func (m *Manager) Logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		m.mu.Lock()
+		delete(m.sessions, c.Value)
+		m.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// userFromRequest resolves the session cookie to a user, or nil.
+func (m *Manager) userFromRequest(r *http.Request) *User {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil || c.Value == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	s, ok := m.sessions[c.Value]
+	if ok && time.Now().After(s.expires) {
+		delete(m.sessions, c.Value)
+		ok = false
+	}
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	// Look the user up again so removed accounts lose access right away.
+	return m.Users.Get(s.username)
+}
+
+// Middleware loads the signed-in user (if any) into the request context.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u := m.userFromRequest(r); u != nil {
+			r = r.WithContext(context.WithValue(r.Context(), userKey, u))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireSetup sends every page to /setup until the first admin exists.
+// Static files and the CA certificate stay reachable.
+func (m *Manager) RequireSetup(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.Users.Empty() && r.URL.Path != "/setup" && r.URL.Path != "/ca.pem" && !strings.HasPrefix(r.URL.Path, "/static/") {
+			http.Redirect(w, r, "/setup", http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole only lets users with at least the given role through.
+// Anonymous visitors are sent to the login page; signed-in users without
+// enough privileges get a 403.
+func (m *Manager) RequireRole(role Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u := UserFromContext(r.Context())
+			if u == nil {
+				http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+				return
+			}
+			if !u.Role.Allows(role) {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UserFromContext returns the signed-in user, or nil for anonymous requests.
+func UserFromContext(ctx context.Context) *User {
+	u, _ := ctx.Value(userKey).(*User)
+	return u
+}
+
+// SafeNext keeps post-login redirects on this site.
+func SafeNext(next string) string {
+	if next == "" || !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/board"
+	}
+	return next
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TeamToken is a signed link that lets a team captain post scores for one
+// team only, without a full account.
+type TeamToken struct {
+	ID        string    `json:"id"`
+	Team      string    `json:"team"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Revoked   bool      `json:"revoked,omitempty"`
+}
+
+// Active reports whether the token is neither revoked nor expired.
+func (t TeamToken) Active() bool {
+	return !t.Revoked && time.Now().Before(t.ExpiresAt)
+}
+
+// tokenPayload is the signed part of the token string.
+type tokenPayload struct {
+	ID   string `json:"id"`
+	Team string `json:"team"`
+	Exp  int64  `json:"exp"`
+}
+
+// TeamTokens issues and checks team tokens. The signing secret and the
+// list of issued tokens (for revocation) are persisted as JSON.
+type TeamTokens struct {
+	mu       sync.RWMutex
+	filename string
+	Secret   string       `json:"secret"`
+	Tokens   []*TeamToken `json:"tokens"`
+}
+
+// LoadTeamTokens boots the token store from tokens.json in dataDir.
+func LoadTeamTokens(dataDir string) *TeamTokens {
+	const tokensFilename = "tokens.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadTeamTokensFile(filepath.Join(dataDir, tokensFilename))
+}
+
+// LoadTeamTokensFile loads the token store from a JSON file, creating a
+// fresh signing secret if there isn't one yet.
+func LoadTeamTokensFile(filename string) *TeamTokens {
+	t := &TeamTokens{filename: filename}
+	if data, err := os.ReadFile(filename); err == nil {
+		_ = json.Unmarshal(data, t)
+	}
+	if t.Secret == "" {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		t.Secret = hex.EncodeToString(b)
+		t.Tokens = nil
+		_ = t.save()
+	}
+	return t
+}
+
+// Issue creates a token for a team that is valid for ttl and saves the store.
+func (t *TeamTokens) Issue(team string, ttl time.Duration) (*TeamToken, error) {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	now := time.Now()
+	tok := &TeamToken{
+		ID:        hex.EncodeToString(id),
+		Team:      team,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Tokens = append(t.Tokens, tok)
+	if err := t.save(); err != nil {
+		// A link that isn't on disk would stop working after a restart
+		t.Tokens = t.Tokens[:len(t.Tokens)-1]
+		return nil, err
+	}
+	return tok, nil
+}
+
+// Revoke marks a token as revoked so its link stops working.
+func (t *TeamTokens) Revoke(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var revoked []*TeamToken
+	for _, tok := range t.Tokens {
+		if tok.ID == id && !tok.Revoked {
+			tok.Revoked = true
+			revoked = append(revoked, tok)
+		}
+	}
+	if err := t.save(); err != nil {
+		// Memory and disk should agree; the admin sees the error and can retry
+		for _, tok := range revoked {
+			tok.Revoked = false
+		}
+		return err
+	}
+	return nil
+}
+
+// Get returns a copy of the token with the given id, or nil.
+func (t *TeamTokens) Get(id string) *TeamToken {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, tok := range t.Tokens {
+		if tok.ID == id {
+			cp := *tok
+			return &cp
+		}
+	}
+	return nil
+}
+
+// List returns all tokens for display, newest first.
+func (t *TeamTokens) List() []TeamToken {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	list := make([]TeamToken, 0, len(t.Tokens))
+	for _, tok := range t.Tokens {
+		list = append(list, *tok)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Sign returns the token string handed out in links and QR codes.
+func (t *TeamTokens) Sign(tok TeamToken) string {
+	payload, _ := json.Marshal(tokenPayload{ID: tok.ID, Team: tok.Team, Exp: tok.ExpiresAt.Unix()})
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + t.signature(body)
+}
+
+// Verify checks a token string and returns the token if it is valid,
+// unexpired and not revoked.
+func (t *TeamTokens) Verify(s string) (*TeamToken, error) {
+	body, sig, ok := strings.Cut(s, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(t.signature(body))) {
+		return nil, ErrInvalidToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var p tokenPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= p.Exp {
+		return nil, ErrInvalidToken
+	}
+	tok := t.Get(p.ID)
+	if tok == nil || !tok.Active() || tok.Team != p.Team {
+		return nil, ErrInvalidToken
+	}
+	return tok, nil
+}
+
+func (t *TeamTokens) signature(body string) string {
+	mac := hmac.New(sha256.New, []byte(t.Secret))
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// save writes the store to disk in one step, so a crash mid-save can't
+// leave a torn file that breaks every printed link. Callers must hold the
+// write lock and undo their change if it fails.
+func (t *TeamTokens) save() error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.WriteFile(t.filename, data, 0600)
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTeamTokensUndoFailedSave(t *testing.T) {
+	dir := t.TempDir()
+	tokens := LoadTeamTokens(dir)
+	tok, err := tokens.Issue("Red", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := tokens.Sign(*tok)
+	// Saves now fail: the directory isn't there
+	tokens.filename = filepath.Join(t.TempDir(), "gone", "tokens.json")
+
+	if tok, err := tokens.Issue("Blue", time.Hour); err == nil || tok != nil {
+		t.Fatalf("Issue = %v, %v; want an error and no token", tok, err)
+	}
+	if n := len(tokens.List()); n != 1 {
+		t.Fatalf("%d tokens in memory, want only the saved one", n)
+	}
+	if err := tokens.Revoke(tok.ID); err == nil {
+		t.Fatal("Revoke succeeded without saving")
+	}
+	if _, err := tokens.Verify(link); err != nil {
+		t.Fatalf("link stopped working though its revocation wasn't saved: %v", err)
+	}
+
+	// What's on disk matches what was in memory before the failures
+	saved := LoadTeamTokens(dir)
+	if got, err := saved.Verify(link); err != nil || got.ID != tok.ID {
+		t.Fatalf("reloaded Verify = %v, %v; want the Red token", got, err)
+	}
+}
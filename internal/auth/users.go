@@ -0,0 +1,254 @@
+// Package auth handles local accounts, login sessions and role checks.
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Role is what a signed-in user is allowed to do.
+type Role string
+
+const (
+	RoleViewer      Role = "viewer"
+	RoleScorekeeper Role = "scorekeeper"
+	RoleAdmin       Role = "admin"
+)
+
+// Roles lists every role from least to most privileged.
+var Roles = []Role{RoleViewer, RoleScorekeeper, RoleAdmin}
+
+var (
+	ErrInvalidLogin = errors.New("invalid username or password")
+	ErrUserExists   = errors.New("user already exists")
+	ErrInvalidRole  = errors.New("invalid role")
+	ErrSetupDone    = errors.New("the first admin already exists")
+)
+
+// rank orders roles so a higher role can do everything a lower one can.
+func (r Role) rank() int {
+	for i, role := range Roles {
+		if role == r {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Allows reports whether r is at least as privileged as min.
+func (r Role) Allows(min Role) bool {
+	return r.Valid() && r.rank() >= min.rank()
+}
+
+// User is a local account.
+type User struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+	Role         Role   `json:"role"`
+}
+
+// Users is the local password store, persisted as JSON.
+type Users struct {
+	mu       sync.RWMutex
+	filename string
+	users    map[string]*User
+}
+
+// LoadUsers boots the user store from users.json in dataDir.
+// A missing file just means nobody has signed up yet.
+func LoadUsers(dataDir string) *Users {
+	const usersFilename = "users.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadUsersFile(filepath.Join(dataDir, usersFilename))
+}
+
+// LoadUsersFile loads the user store from a JSON file.
+func LoadUsersFile(filename string) *Users {
+	u := &Users{
+		filename: filename,
+		users:    make(map[string]*User),
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return u
+	}
+
+	var list []*User
+	if err := json.Unmarshal(data, &list); err != nil {
+		return u
+	}
+	for _, usr := range list {
+		if usr != nil && usr.Username != "" {
+			u.users[normalize(usr.Username)] = usr
+		}
+	}
+	return u
+}
+
+// normalize makes usernames case-insensitive.
+func normalize(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// Empty reports whether no accounts exist yet (first run).
+func (u *Users) Empty() bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return len(u.users) == 0
+}
+
+// Get returns the user with the given name, or nil.
+func (u *Users) Get(username string) *User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.users[normalize(username)]
+}
+
+// List returns all users sorted by username.
+func (u *Users) List() []User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	list := make([]User, 0, len(u.users))
+	for _, usr := range u.users {
+		list = append(list, *usr)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Username < list[j].Username
+	})
+	return list
+}
+
+// Add creates a new account with a bcrypt-hashed password and saves the store.
+func (u *Users) Add(username, password string, role Role) error {
+	return u.add(username, password, role, false)
+}
+
+// CreateFirstAdmin creates the first account, an admin, on first run. The
+// check that there are no accounts yet and the insert happen under one
+// lock, so of two setups racing each other only one gets through; the
+// other gets ErrSetupDone.
+func (u *Users) CreateFirstAdmin(username, password string) error {
+	return u.add(username, password, RoleAdmin, true)
+}
+
+func (u *Users) add(username, password string, role Role, first bool) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if first && len(u.users) > 0 {
+		return ErrSetupDone
+	}
+	key := normalize(username)
+	if _, ok := u.users[key]; ok {
+		return ErrUserExists
+	}
+	u.users[key] = &User{
+		Username:     strings.TrimSpace(username),
+		PasswordHash: string(hash),
+		Role:         role,
+	}
+	if err := u.save(); err != nil {
+		// An account that isn't on disk would be gone after a restart
+		delete(u.users, key)
+		return err
+	}
+	return nil
+}
+
+// Remove deletes an account and saves the store.
+func (u *Users) Remove(username string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	key := normalize(username)
+	usr, ok := u.users[key]
+	if !ok {
+		return nil
+	}
+	delete(u.users, key)
+	if err := u.save(); err != nil {
+		u.users[key] = usr
+		return err
+	}
+	return nil
+}
+
+// CountRole returns how many accounts have the given role.
+func (u *Users) CountRole(role Role) int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	n := 0
+	for _, usr := range u.users {
+		if usr.Role == role {
+			n++
+		}
+	}
+	return n
+}
+
+// Authenticate checks a username/password pair and returns the user on success.
+func (u *Users) Authenticate(username, password string) (*User, error) {
+	usr := u.Get(username)
+	if usr == nil {
+		// Burn comparable time so unknown usernames aren't obvious.
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return nil, ErrInvalidLogin
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(usr.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidLogin
+	}
+	return usr, nil
+}
+
+var (
+	dummyOnce sync.Once
+	dummy     []byte
+)
+
+// dummyHash returns a throwaway hash used when the username is unknown.
+func dummyHash() []byte {
+	dummyOnce.Do(func() {
+		dummy, _ = bcrypt.GenerateFromPassword([]byte("not-a-password"), bcrypt.DefaultCost)
+	})
+	return dummy
+}
+
+// save writes the store to disk in one step, so a crash mid-save can't
+// leave a torn file that locks everyone out. Callers must hold the write
+// lock and undo their change if it fails.
+func (u *Users) save() error {
+	list := make([]*User, 0, len(u.users))
+	for _, usr := range u.users {
+		list = append(list, usr)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Username < list[j].Username
+	})
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.WriteFile(u.filename, data, 0600)
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestCreateFirstAdminOnlyOnce(t *testing.T) {
+	u := LoadUsers(t.TempDir())
+
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = u.CreateFirstAdmin("admin"+strconv.Itoa(i), "password1")
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrSetupDone):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if created != 1 || len(u.List()) != 1 {
+		t.Fatalf("%d setups succeeded and %d accounts exist; want 1 of each", created, len(u.List()))
+	}
+	if u.List()[0].Role != RoleAdmin {
+		t.Fatalf("first account is a %s, want an admin", u.List()[0].Role)
+	}
+}
+
+func TestUsersUndoFailedSave(t *testing.T) {
+	u := LoadUsers(t.TempDir())
+	if err := u.Add("alice", "password1", RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	// Saves now fail: the directory isn't there
+	u.filename = filepath.Join(t.TempDir(), "gone", "users.json")
+
+	if err := u.Add("bob", "password1", RoleViewer); err == nil {
+		t.Fatal("Add succeeded without saving")
+	}
+	if u.Get("bob") != nil {
+		t.Fatal("bob was kept in memory but never saved")
+	}
+	if err := u.Remove("alice"); err == nil {
+		t.Fatal("Remove succeeded without saving")
+	}
+	if u.Get("alice") == nil {
+		t.Fatal("alice was dropped from memory but is still on disk")
+	}
+}
@@ -0,0 +1,207 @@
+// Package certs makes the certificates for serving HTTPS on a LAN without
+// buying one: a local CA, created once, signs a server certificate for this
+// machine's names and addresses. Devices that should trust the server
+// install the CA certificate (ca.pem) once.
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Files in the certificate directory.
+const (
+	CAFile     = "ca.pem"
+	caKeyFile  = "ca-key.pem"
+	CertFile   = "cert.pem"
+	KeyFile    = "key.pem"
+	caValidity = 10 * 365 * 24 * time.Hour
+	// Browsers reject server certificates valid for much longer than a year
+	certValidity = 397 * 24 * time.Hour
+	// renewBefore is how close to expiry a certificate gets replaced.
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// Dir is where the certificates are kept in the data directory.
+func Dir(dataDir string) string {
+	return filepath.Join(dataDir, "tls")
+}
+
+// Ensure makes sure dir holds a CA and a server certificate, valid for a
+// while yet, that covers hosts. It only makes what's missing or out of
+// date, so the CA devices already trust stays the same. It returns the
+// certificate and key files to serve.
+func Ensure(dir string, hosts []string) (certFile, keyFile string, err error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", "", err
+	}
+	ca, caKey, err := loadPair(filepath.Join(dir, CAFile), filepath.Join(dir, caKeyFile))
+	if err != nil || time.Until(ca.NotAfter) < renewBefore {
+		if ca, caKey, err = newCA(dir); err != nil {
+			return "", "", fmt.Errorf("making the local CA: %w", err)
+		}
+	}
+
+	certFile, keyFile = filepath.Join(dir, CertFile), filepath.Join(dir, KeyFile)
+	if cert, _, err := loadPair(certFile, keyFile); err == nil && current(cert, ca, hosts) {
+		return certFile, keyFile, nil
+	}
+	if err := newCert(dir, ca, caKey, hosts); err != nil {
+		return "", "", fmt.Errorf("making the server certificate: %w", err)
+	}
+	return certFile, keyFile, nil
+}
+
+// Hosts lists the names and addresses this machine can be reached at:
+// localhost, its hostname and the addresses of its network interfaces.
+func Hosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if name, err := os.Hostname(); err == nil && name != "" {
+		hosts = append(hosts, name)
+		if !strings.Contains(name, ".") {
+			hosts = append(hosts, name+".local")
+		}
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if ipnet, ok := a.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && !ipnet.IP.IsLinkLocalUnicast() {
+				hosts = append(hosts, ipnet.IP.String())
+			}
+		}
+	}
+	return hosts
+}
+
+// current reports whether cert was signed by ca, covers every host and
+// isn't about to expire.
+func current(cert, ca *x509.Certificate, hosts []string) bool {
+	if time.Until(cert.NotAfter) < renewBefore || cert.CheckSignatureFrom(ca) != nil {
+		return false
+	}
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func newCA(dir string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	name, _ := os.Hostname()
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial(),
+		Subject:               pkix.Name{Organization: []string{"Score Board"}, CommonName: "Score Board local CA " + name},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writePair(filepath.Join(dir, CAFile), filepath.Join(dir, caKeyFile), der, key); err != nil {
+		return nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	return ca, key, err
+}
+
+func newCert(dir string, ca *x509.Certificate, caKey *ecdsa.PrivateKey, hosts []string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	notAfter := now.Add(certValidity)
+	if ca.NotAfter.Before(notAfter) {
+		notAfter = ca.NotAfter
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(),
+		Subject:      pkix.Name{Organization: []string{"Score Board"}, CommonName: hosts[0]},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else if !slices.Contains(tmpl.DNSNames, h) {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	return writePair(filepath.Join(dir, CertFile), filepath.Join(dir, KeyFile), der, key)
+}
+
+func serial() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+// writePair writes a certificate and its key as PEM; the key is only
+// readable by us.
+func writePair(certFile, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// loadPair reads a certificate and key written by writePair.
+func loadPair(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cb, _ := pem.Decode(certPEM)
+	kb, _ := pem.Decode(keyPEM)
+	if cb == nil || kb == nil {
+		return nil, nil, errors.New("not a PEM file")
+	}
+	cert, err := x509.ParseCertificate(cb.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(kb.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, nil, errors.New("key doesn't match the certificate")
+	}
+	return cert, key, nil
+}
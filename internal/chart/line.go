@@ -0,0 +1,190 @@
+// Package chart draws simple charts as standalone SVG documents, so they
+// can be shown inline, used as an <img> or downloaded and shared as is.
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+)
+
+// Series is one line on the chart.
+type Series struct {
+	Name   string
+	Color  string
+	Values []float64 // one value per label
+}
+
+// Group marks a run of labels that belong together (e.g. the rounds of one
+// game); it is drawn as a shaded band with its name on top.
+type Group struct {
+	Name  string
+	Start int // first label index
+	End   int // last label index, inclusive
+}
+
+// LineChart is a line chart with a shared x axis of labels.
+type LineChart struct {
+	Title  string
+	Labels []string
+	Groups []Group
+	Series []Series
+	Width  int // defaults to 800
+	Height int // defaults to 420
+}
+
+// Layout margins around the plot area.
+const (
+	marginTop    = 56
+	marginBottom = 48
+	marginLeft   = 56
+	legendWidth  = 170
+	background   = "#0f1018"
+	gridColor    = "rgba(255,255,255,.12)"
+	textColor    = "#ffffff"
+	maxXLabels   = 12
+	maxDots      = 40
+)
+
+// WriteSVG renders the chart as a complete SVG document.
+func (c LineChart) WriteSVG(w io.Writer) error {
+	width, height := c.Width, c.Height
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		height = 420
+	}
+	plotW := float64(width - marginLeft - legendWidth)
+	plotH := float64(height - marginTop - marginBottom)
+
+	lo, hi := c.bounds()
+	ticks := niceTicks(lo, hi, 5)
+	lo, hi = ticks[0], ticks[len(ticks)-1]
+
+	n := len(c.Labels)
+	x := func(i int) float64 {
+		if n <= 1 {
+			return marginLeft + plotW/2
+		}
+		return marginLeft + float64(i)*plotW/float64(n-1)
+	}
+	y := func(v float64) float64 {
+		return marginTop + plotH - (v-lo)/(hi-lo)*plotH
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="Helvetica, Arial, sans-serif" role="img">`, width, height, width, height)
+	fmt.Fprintf(&b, `<title>%s</title>`, esc(c.Title))
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, background)
+	fmt.Fprintf(&b, `<text x="%d" y="32" fill="%s" font-size="20" font-weight="bold">%s</text>`, marginLeft, textColor, esc(c.Title))
+
+	// Game bands behind everything else.
+	for i, g := range c.Groups {
+		if g.Start < 0 || g.End >= n || g.Start > g.End {
+			continue
+		}
+		x0, x1 := x(g.Start), x(g.End)
+		if g.Start > 0 {
+			x0 = (x(g.Start-1) + x0) / 2
+		}
+		if g.End < n-1 {
+			x1 = (x1 + x(g.End+1)) / 2
+		}
+		if i%2 == 1 {
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%.1f" fill="rgba(255,255,255,.04)"/>`, x0, marginTop, x1-x0, plotH)
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="%s" opacity=".6" font-size="12" text-anchor="middle">%s</text>`, (x0+x1)/2, marginTop-6, textColor, esc(g.Name))
+	}
+
+	// Horizontal grid with y values.
+	for _, t := range ticks {
+		fmt.Fprintf(&b, `<line x1="%d" x2="%.1f" y1="%.1f" y2="%.1f" stroke="%s"/>`, marginLeft, marginLeft+plotW, y(t), y(t), gridColor)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" fill="%s" opacity=".7" font-size="12" text-anchor="end" dominant-baseline="middle">%s</text>`, marginLeft-8, y(t), textColor, formatNum(t))
+	}
+
+	// X labels, thinned out when there are many.
+	step := max(1, int(math.Ceil(float64(n)/maxXLabels)))
+	for i := 0; i < n; i += step {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" fill="%s" opacity=".7" font-size="12" text-anchor="middle">%s</text>`, x(i), marginTop+plotH+20, textColor, esc(c.Labels[i]))
+	}
+
+	for _, s := range c.Series {
+		if len(s.Values) == 0 {
+			continue
+		}
+		pts := make([]string, 0, len(s.Values))
+		for i, v := range s.Values {
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", x(i), y(v)))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="3" stroke-linejoin="round" stroke-linecap="round"/>`, strings.Join(pts, " "), esc(s.Color))
+		if len(s.Values) <= maxDots {
+			for i, v := range s.Values {
+				fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3.5" fill="%s"/>`, x(i), y(v), esc(s.Color))
+			}
+		}
+	}
+
+	// Legend with each series' last value.
+	lx := width - legendWidth + 16
+	for i, s := range c.Series {
+		ly := marginTop + 8 + i*26
+		last := 0.0
+		if len(s.Values) > 0 {
+			last = s.Values[len(s.Values)-1]
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="14" height="14" rx="3" fill="%s"/>`, lx, ly-7, esc(s.Color))
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s" font-size="14" dominant-baseline="middle">%s <tspan font-weight="bold">%s</tspan></text>`, lx+22, ly, textColor, esc(s.Name), formatNum(last))
+	}
+
+	b.WriteString(`</svg>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// bounds returns the value range to plot, always including zero.
+func (c LineChart) bounds() (lo, hi float64) {
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if lo == hi {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// niceTicks returns evenly spaced round numbers (steps of 1, 2 or 5 times a
+// power of ten) covering [lo, hi] with roughly count intervals.
+func niceTicks(lo, hi float64, count int) []float64 {
+	raw := (hi - lo) / float64(count)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 5, 10} {
+		step = m * mag
+		if step >= raw {
+			break
+		}
+	}
+	start := math.Floor(lo/step) * step
+	end := math.Ceil(hi/step) * step
+	var ticks []float64
+	for t := start; t <= end+step/2; t += step {
+		ticks = append(ticks, math.Round(t/step)*step)
+	}
+	return ticks
+}
+
+func formatNum(f float64) string {
+	if f == math.Trunc(f) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%.1f", f)
+}
+
+func esc(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,257 @@
+// Package cli is the score-board command line. Without a command the
+// binary starts the web server like it always did; the other commands work
+// on the same data files, so setup can be scripted before an event and
+// data fixed over SSH.
+//
+// The server keeps the board in memory and writes it back on every change,
+// so it holds a lock on the data directory while it runs, and commands that
+// change data take the same lock and refuse to run alongside it.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+const usage = `Usage: score-board [settings] [command] [flags]
+
+Commands:
+  serve                        start the web server (the default)
+  board show                   print the board and standings
+  board new NAME               start an empty board (after a reset)
+  team add NAME                add a team; -members a,b,c -color #RRGGBB
+  game add NAME                add a game to every team; -scoring, -rounds
+  score add TEAM GAME SCORE    add a score to the next round; -round NAME
+  export                       write the board out; -format json|pdf|png -o FILE
+  import FILE                  replace the board with a JSON export; -force
+  reset                        archive and clear the board; -yes
+  tui                          live standings and score entry in the terminal
+                               against a running server; -url, -user, -ca
+
+Settings (also read from score-board.yaml/.toml, or -config FILE, and
+SCORE_BOARD_* environment variables; flags win, then the environment):
+  -config FILE                 YAML or TOML config file
+  -addr ADDR, -port PORT       where to listen; default :8080
+  -data DIR                    data directory; default ./data
+  -tls-cert FILE -tls-key FILE serve HTTPS
+  -tls-auto                    serve HTTPS with a certificate from a local CA
+                               kept in DATA/tls; -tls-hosts extra,names
+  -tls-redirect ADDR           plain HTTP listener that redirects to HTTPS
+  -colors #RRGGBB,...          default team colors
+  -shutdown-timeout DURATION   time for requests to finish on Ctrl+C; 10s
+  -session-ttl, -idempotency-ttl, -sync-log-ttl DURATION
+
+Commands that change data refuse to run while a server is using the same
+data directory, since it would write its own copy of the board over
+theirs; stop it first. board show and export only read, and tui talks to
+the server instead, so they're safe while it runs; tui asks for the
+password unless SCORE_BOARD_PASSWORD is set.
+`
+
+// errUsage means the arguments were wrong; the usage text is printed.
+var errUsage = errors.New("usage")
+
+// command is one "score-board <name>" command. Commands that write take
+// the data directory's lock first.
+type command struct {
+	name   string
+	run    func(a *app, args []string) error
+	writes bool
+}
+
+// app carries what commands need, so output can go anywhere.
+type app struct {
+	out io.Writer
+	cfg *config.Config
+}
+
+var commands = []command{
+	{"board show", (*app).boardShow, false},
+	{"board new", (*app).boardNew, true},
+	{"team add", (*app).teamAdd, true},
+	{"game add", (*app).gameAdd, true},
+	{"score add", (*app).scoreAdd, true},
+	{"export", (*app).export, false},
+	{"import", (*app).importBoard, true},
+	{"reset", (*app).reset, true},
+	{"tui", (*app).tui, false},
+}
+
+// Run runs the command in args and returns the exit code. serve starts the
+// web server.
+func Run(args []string, stdout, stderr io.Writer, serve func(cfg *config.Config) error) int {
+	cfg, args, err := config.Load(args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprint(stdout, usage)
+		return 0
+	case err != nil:
+		fmt.Fprintln(stderr, "score-board:", err)
+		return 2
+	}
+	templates.DefaultColors = cfg.Defaults.Colors
+	a := &app{out: stdout, cfg: cfg}
+	if len(args) == 0 || args[0] == "serve" {
+		if len(args) > 1 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		if err := serve(cfg); err != nil {
+			fmt.Fprintln(stderr, "score-board:", err)
+			return 1
+		}
+		return 0
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	for _, c := range commands {
+		words := strings.Fields(c.name)
+		if len(args) < len(words) || strings.Join(args[:len(words)], " ") != c.name {
+			continue
+		}
+		err := a.run(c, args[len(words):])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+			fmt.Fprint(stderr, usage)
+			return 2
+		default:
+			fmt.Fprintln(stderr, "score-board "+c.name+":", err)
+			return 1
+		}
+	}
+	fmt.Fprintf(stderr, "score-board: unknown command %q\n\n%s", strings.Join(args, " "), usage)
+	return 2
+}
+
+// run runs c, holding the data directory's lock if it writes.
+func (a *app) run(c command, args []string) error {
+	if !c.writes {
+		return c.run(a, args)
+	}
+	lock, err := store.LockDataDir(a.cfg.DataDir)
+	if errors.Is(err, store.ErrLocked) {
+		return fmt.Errorf("%w; stop the server first (tui works while it runs)", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	return c.run(a, args)
+}
+
+// flags returns a quiet flag set; Run prints the usage on errors.
+func flags(name string) *flag.FlagSet {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	return set
+}
+
+// parseArgs lets flags and arguments come in any order, as in
+// "team add Red -color #C50000". Negative numbers are arguments, so
+// "score add Red Darts -5" works.
+func parseArgs(set *flag.FlagSet, args []string, want int) ([]string, error) {
+	var flagArgs, pos []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			pos = append(pos, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			pos = append(pos, a)
+			continue
+		}
+		if _, err := strconv.Atoi(a); err == nil {
+			pos = append(pos, a)
+			continue
+		}
+		flagArgs = append(flagArgs, a)
+		name := strings.TrimLeft(a, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := set.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flagArgs = append(flagArgs, args[i])
+		}
+	}
+	if err := set.Parse(flagArgs); err != nil {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if len(pos) != want {
+		return nil, errUsage
+	}
+	return pos, nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// check turns the first form-style validation error into an error.
+func check(errs validate.Errors, fields ...string) error {
+	for _, f := range fields {
+		if msg := errs.Get(f); msg != "" {
+			return fmt.Errorf("%s %s", f, msg)
+		}
+	}
+	return nil
+}
+
+// loadBoard reads the saved board. Unlike the server it refuses to go on
+// with a file it can't read, rather than start over with an empty board.
+func (a *app) loadBoard() (*store.ScoreBoard, error) {
+	path := filepath.Join(a.cfg.DataDir, store.DBFilename)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store.NewBoard(""), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := &store.ScoreBoard{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// loadOpenBoard is loadBoard for commands that change the board, which a
+// finished event doesn't allow.
+func (a *app) loadOpenBoard() (*store.ScoreBoard, error) {
+	b, err := a.loadBoard()
+	if err == nil && b.Finished() {
+		err = errors.New("the event is finished; reset the board to start a new one")
+	}
+	return b, err
+}
+
+// saveBoard bumps the version, so forms open in a browser go stale, and
+// writes the board.
+func (a *app) saveBoard(b *store.ScoreBoard) error {
+	if err := os.MkdirAll(a.cfg.DataDir, 0755); err != nil {
+		return err
+	}
+	b.Version++
+	return b.SaveToJSON(filepath.Join(a.cfg.DataDir, store.DBFilename))
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+func TestWritesWaitForTheServer(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) (int, string) {
+		var out, errOut bytes.Buffer
+		code := Run(append([]string{"-data", dir}, args...), &out, &errOut, func(*config.Config) error { return nil })
+		return code, errOut.String()
+	}
+
+	// A running server holds the lock
+	lock, err := store.LockDataDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, msg := run("team", "add", "Red"); code != 1 || !strings.Contains(msg, "stop the server first") {
+		t.Fatalf("team add while locked: exit %d, %q; want it refused", code, msg)
+	}
+	if code, msg := run("board", "show"); code != 0 {
+		t.Fatalf("board show while locked: exit %d, %q; want it to run", code, msg)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if code, msg := run("team", "add", "Red"); code != 0 {
+		t.Fatalf("team add once unlocked: exit %d, %q", code, msg)
+	}
+	// The command let go of the lock when it finished
+	lock, err = store.LockDataDir(dir)
+	if err != nil {
+		t.Fatalf("lock after team add: %v", err)
+	}
+	lock.Unlock()
+}
@@ -0,0 +1,430 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/mrjxtr-dev/score-board/internal/certs"
+	"github.com/mrjxtr-dev/score-board/internal/export"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/tui"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+// boardShow prints the board name, standings and games.
+func (a *app) boardShow(args []string) error {
+	if _, err := parseArgs(flags("board show"), args, 0); err != nil {
+		return err
+	}
+	b, err := a.loadBoard()
+	if err != nil {
+		return err
+	}
+	if b.BoardName == "" && len(b.Teams) == 0 {
+		fmt.Fprintln(a.out, "No board yet. Start one with: score-board board new NAME")
+		return nil
+	}
+
+	fmt.Fprintln(a.out, b.BoardName)
+	if b.Finished() {
+		fmt.Fprintln(a.out, "Finished "+b.FinishedAt.Local().Format("Jan 2, 2006 15:04"))
+	}
+	fmt.Fprintln(a.out)
+	if len(b.Teams) == 0 {
+		fmt.Fprintln(a.out, "No teams yet.")
+	} else {
+		tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "#\tTEAM\tTOTAL\tMEMBERS")
+		for _, s := range b.Standings() {
+			var members []string
+			for _, t := range b.Teams {
+				if t != nil && t.TeamName == s.Team {
+					members = t.Members
+				}
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%d\t%s\n", s.Rank, s.Team, s.Total, strings.Join(members, ", "))
+		}
+		_ = tw.Flush()
+	}
+
+	games := templates.UniqueGameNames(b)
+	if len(games) > 0 {
+		fmt.Fprintln(a.out)
+		for _, name := range games {
+			fmt.Fprintf(a.out, "%s — %s\n", name, templates.ScoringLabel(templates.GameSettings(b, name)))
+		}
+	}
+	return nil
+}
+
+// boardNew names a fresh board. It won't replace a board that has teams;
+// reset that first so it gets archived.
+func (a *app) boardNew(args []string) error {
+	pos, err := parseArgs(flags("board new"), args, 1)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(pos[0])
+	errs := validate.Errors{}
+	errs.Name("name", name, validate.MaxBoardName)
+	if err := check(errs, "name"); err != nil {
+		return err
+	}
+	b, err := a.loadBoard()
+	if err != nil {
+		return err
+	}
+	if len(b.Teams) > 0 {
+		return errors.New("the board already has teams; run reset first")
+	}
+	nb := store.NewBoard(name)
+	nb.Version = b.Version
+	if err := a.saveBoard(nb); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Started board %q\n", name)
+	return nil
+}
+
+// teamAdd adds a team with every game the board already has.
+func (a *app) teamAdd(args []string) error {
+	fs := flags("team add")
+	members := fs.String("members", "", "comma-separated member names")
+	color := fs.String("color", "", "team color as #RRGGBB")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	b, err := a.loadOpenBoard()
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(pos[0])
+	var taken []string
+	for _, t := range b.Teams {
+		if t != nil {
+			taken = append(taken, t.TeamName)
+		}
+	}
+	errs := validate.Errors{}
+	errs.Name("name", name, validate.MaxTeamName)
+	errs.Unique("name", name, taken)
+	list := errs.Members("members", *members)
+	if *color == "" {
+		*color = templates.DefaultColorHex(len(b.Teams) + 1)
+	}
+	errs.Color("color", *color)
+	if err := check(errs, "name", "members", "color"); err != nil {
+		return err
+	}
+
+	b.AddTeam(&store.Team{
+		TeamName:  name,
+		TeamColor: map[string]string{"color": *color},
+		Members:   list,
+		Games:     b.BlankGames(),
+	})
+	if err := a.saveBoard(b); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Added team %q\n", name)
+	return nil
+}
+
+// gameAdd adds a game to every team.
+func (a *app) gameAdd(args []string) error {
+	fs := flags("game add")
+	scoring := fs.String("scoring", store.ScoringSum, "how rounds add up: "+strings.Join(store.ScoringModes, ", "))
+	rounds := fs.Int("rounds", 0, "round limit, 0 for none")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	b, err := a.loadOpenBoard()
+	if err != nil {
+		return err
+	}
+	if len(b.Teams) == 0 {
+		return errors.New("add teams first")
+	}
+
+	name := strings.TrimSpace(pos[0])
+	errs := validate.Errors{}
+	errs.Name("name", name, validate.MaxGameName)
+	errs.Unique("name", name, templates.UniqueGameNames(b))
+	if !slices.Contains(store.ScoringModes, *scoring) {
+		errs.Add("scoring", "must be one of "+strings.Join(store.ScoringModes, ", "))
+	}
+	errs.Int("rounds", strconv.Itoa(*rounds), 0, validate.MaxRoundLimit)
+	if err := check(errs, "name", "scoring", "rounds"); err != nil {
+		return err
+	}
+
+	if *scoring == store.ScoringSum {
+		*scoring = "" // the default isn't written out
+	}
+	for _, t := range b.Teams {
+		if t != nil {
+			t.Games = append(t.Games, store.Game{
+				GameName:   name,
+				Rounds:     make(map[string]int),
+				Scoring:    *scoring,
+				RoundLimit: *rounds,
+			})
+		}
+	}
+	if err := a.saveBoard(b); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Added game %q\n", name)
+	return nil
+}
+
+// scoreAdd records a score for a team's game, in its next round unless
+// -round says otherwise.
+func (a *app) scoreAdd(args []string) error {
+	fs := flags("score add")
+	round := fs.String("round", "", "round name; defaults to the next round")
+	pos, err := parseArgs(fs, args, 3)
+	if err != nil {
+		return err
+	}
+	b, err := a.loadOpenBoard()
+	if err != nil {
+		return err
+	}
+
+	teamName, gameName := strings.TrimSpace(pos[0]), strings.TrimSpace(pos[1])
+	rn := strings.TrimSpace(*round)
+	errs := validate.Errors{}
+	score, _ := errs.Score("score", pos[2])
+	errs.OptionalName("round", rn, validate.MaxRoundName)
+	if err := check(errs, "score", "round"); err != nil {
+		return err
+	}
+	var team *store.Team
+	for _, t := range b.Teams {
+		if t != nil && t.TeamName == teamName {
+			team = t
+		}
+	}
+	if team == nil {
+		return fmt.Errorf("no team %q", teamName)
+	}
+	var game *store.Game
+	for i := range team.Games {
+		if team.Games[i].GameName == gameName {
+			game = &team.Games[i]
+		}
+	}
+	if game == nil {
+		return fmt.Errorf("no game %q", gameName)
+	}
+	if game.Rounds == nil {
+		game.Rounds = make(map[string]int)
+	}
+	if _, exists := game.Rounds[rn]; (!exists || rn == "") && game.RoundsFull() {
+		return fmt.Errorf("round limit reached (%d rounds)", game.RoundLimit)
+	}
+	if rn == "" {
+		rn = strconv.Itoa(templates.NextRoundForGame(*game))
+	}
+	game.Rounds[rn] = score
+	if err := a.saveBoard(b); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%s · %s round %s: %d (total %d)\n", team.TeamName, game.GameName, rn, score, team.TotalScore())
+	return nil
+}
+
+// export writes the board as JSON (what import reads back) or its results
+// as a PDF or PNG.
+func (a *app) export(args []string) error {
+	fs := flags("export")
+	format := fs.String("format", "json", "json, pdf or png")
+	outFile := fs.String("o", "", "output file; defaults to stdout")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	b, err := a.loadBoard()
+	if err != nil {
+		return err
+	}
+
+	var write func(w io.Writer) error
+	switch *format {
+	case "json":
+		write = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(b)
+		}
+	case "pdf":
+		write = func(w io.Writer) error { return export.WritePDF(w, export.FromBoard(b, time.Now())) }
+	case "png":
+		write = func(w io.Writer) error { return export.WritePNG(w, export.FromBoard(b, time.Now())) }
+	default:
+		return fmt.Errorf("unknown format %q; use json, pdf or png", *format)
+	}
+
+	if *outFile == "" || *outFile == "-" {
+		return write(a.out)
+	}
+	f, err := os.Create(*outFile)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// importBoard replaces the board with a JSON export. A board that already
+// has teams is only replaced with -force.
+func (a *app) importBoard(args []string) error {
+	fs := flags("import")
+	force := fs.Bool("force", false, "replace a board that already has teams")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if pos[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(pos[0])
+	}
+	if err != nil {
+		return err
+	}
+	nb := &store.ScoreBoard{}
+	if err := json.Unmarshal(data, nb); err != nil {
+		return fmt.Errorf("%s: %w", pos[0], err)
+	}
+	if err := checkImport(nb); err != nil {
+		return fmt.Errorf("%s: %w", pos[0], err)
+	}
+
+	b, err := a.loadBoard()
+	if err != nil {
+		return err
+	}
+	if len(b.Teams) > 0 && !*force {
+		return errors.New("the board already has teams; use -force to replace it")
+	}
+	nb.Version = max(nb.Version, b.Version)
+	if err := a.saveBoard(nb); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Imported %q with %d teams\n", nb.BoardName, len(nb.Teams))
+	return nil
+}
+
+// checkImport makes sure an imported board is something the app can show:
+// valid, unique team names and named games with valid scores.
+func checkImport(b *store.ScoreBoard) error {
+	errs := validate.Errors{}
+	errs.OptionalName("board", b.BoardName, validate.MaxBoardName)
+	var names []string
+	teams := b.Teams[:0]
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		errs.Name("team", t.TeamName, validate.MaxTeamName)
+		errs.Unique("team", t.TeamName, names)
+		names = append(names, t.TeamName)
+		for _, g := range t.Games {
+			errs.Name("game", g.GameName, validate.MaxGameName)
+			for rn, sc := range g.Rounds {
+				errs.OptionalName("round", rn, validate.MaxRoundName)
+				errs.Score("score", strconv.Itoa(sc))
+			}
+		}
+		teams = append(teams, t)
+	}
+	b.Teams = teams
+	return check(errs, "board", "team", "game", "round", "score")
+}
+
+// reset archives an unfinished board that has teams, like the Reset
+// button does, and clears it.
+func (a *app) reset(args []string) error {
+	fs := flags("reset")
+	yes := fs.Bool("yes", false, "really clear the board")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if !*yes {
+		return errors.New("this clears the board; run again with -yes")
+	}
+	b, err := a.loadBoard()
+	if err != nil {
+		return err
+	}
+	if len(b.Teams) > 0 && !b.Finished() {
+		if _, err := store.LoadArchive(a.cfg.DataDir).Add(b); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Archived %q\n", b.BoardName)
+	}
+	nb := store.NewBoard("")
+	nb.Version = b.Version
+	if err := a.saveBoard(nb); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.out, "Board cleared")
+	return nil
+}
+
+// tui signs in to a running server and opens the terminal scoreboard.
+func (a *app) tui(args []string) error {
+	fs := flags("tui")
+	serverURL := fs.String("url", a.cfg.LocalURL(), "address of the running server")
+	user := fs.String("user", "", "username to sign in with")
+	caFile := fs.String("ca", "", "CA certificate to trust for HTTPS (default: the local CA in the data directory)")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *caFile == "" {
+		local := filepath.Join(certs.Dir(a.cfg.DataDir), certs.CAFile)
+		if _, err := os.Stat(local); err == nil {
+			*caFile = local
+		}
+	}
+	if *user == "" {
+		return errors.New("-user is required")
+	}
+	password := os.Getenv("SCORE_BOARD_PASSWORD")
+	if password == "" {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return errors.New("no terminal to ask for the password; set SCORE_BOARD_PASSWORD")
+		}
+		fmt.Fprintf(a.out, "Password for %s: ", *user)
+		raw, err := term.ReadPassword(fd)
+		fmt.Fprintln(a.out)
+		if err != nil {
+			return err
+		}
+		password = string(raw)
+	}
+	return tui.Run(context.Background(), tui.Options{URL: *serverURL, User: *user, Password: password, CAFile: *caFile})
+}
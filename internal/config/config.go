@@ -1,43 +1,342 @@
-// Package config
+// Package config holds the server settings. Each one can come from a YAML
+// or TOML config file, an environment variable (a .env file is read into
+// the environment first) or a command-line flag; flags win over the
+// environment, which wins over the file, which wins over the defaults.
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
 )
 
 type Config struct {
-	PORT     string
-	Defaults Defaults
+	// Addr is the address to listen on, like ":8080" or "127.0.0.1:8080".
+	Addr    string
+	DataDir string
+	// File is the config file that was read, if any.
+	File string
+	// ShutdownTimeout is how long requests get to finish when the server
+	// is stopped.
+	ShutdownTimeout time.Duration
+	TLS             TLS
+	Defaults        Defaults
+	Auth            Auth
+	Retention       Retention
+}
+
+// TLS serves HTTPS, with the given certificate and key or, with Auto, a
+// certificate signed by a local CA that is made and kept in the data
+// directory.
+type TLS struct {
+	CertFile string
+	KeyFile  string
+	Auto     bool
+	// Hosts are extra names and addresses for the automatic certificate,
+	// on top of this machine's own.
+	Hosts []string
+	// Redirect is an address for a plain HTTP listener that sends
+	// visitors over to HTTPS, like ":80".
+	Redirect string
+}
+
+// Enabled reports whether the server speaks HTTPS.
+func (t TLS) Enabled() bool {
+	return t.Auto || t.CertFile != ""
 }
 
 type Defaults struct {
-	Colors map[string]string
+	// Colors are handed out to new teams in slot order.
+	Colors []string
 }
 
-var DefaulColors = map[string]string{
-	"pink":   "#D50059",
-	"red":    "#C50000",
-	"blue":   "#1D03AF",
-	"yellow": "#FFBB02",
+type Auth struct {
+	SessionTTL time.Duration
 }
 
-func LoadConfig() *Config {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+// Retention is how long the bookkeeping files remember things.
+type Retention struct {
+	Idempotency time.Duration
+	SyncLog     time.Duration
+}
 
-	if err := godotenv.Load(); err != nil {
-		log.Println("Error loading .env file")
-	}
+// DefaultColors are pink, red, blue and yellow.
+var DefaultColors = []string{"#D50059", "#C50000", "#1D03AF", "#FFBB02"}
+
+// configFiles are looked for in the working directory when no config file
+// is named.
+var configFiles = []string{"score-board.yaml", "score-board.yml", "score-board.toml"}
 
+// Default returns the settings used when nothing is configured.
+func Default() *Config {
 	return &Config{
-		PORT: port,
-		Defaults: Defaults{
-			Colors: DefaulColors,
+		Addr:            ":8080",
+		DataDir:         "./data",
+		ShutdownTimeout: 10 * time.Second,
+		Defaults:        Defaults{Colors: DefaultColors},
+		Auth:            Auth{SessionTTL: auth.DefaultSessionTTL},
+		Retention: Retention{
+			Idempotency: store.DefaultIdempotencyTTL,
+			SyncLog:     store.DefaultSyncLogTTL,
 		},
 	}
 }
+
+// setting is one configurable value, known by its key in the config file,
+// its environment variable and its flag.
+type setting struct {
+	key   string
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, v string) error
+}
+
+var settings = []setting{
+	{"addr", "SCORE_BOARD_ADDR", "addr", "address to listen on", func(c *Config, v string) error {
+		c.Addr = v
+		return nil
+	}},
+	{"port", "PORT", "port", "port to listen on, keeping the host from addr", func(c *Config, v string) error {
+		host, _, err := net.SplitHostPort(c.Addr)
+		if err != nil {
+			host = ""
+		}
+		c.Addr = net.JoinHostPort(host, v)
+		return nil
+	}},
+	{"data_dir", "SCORE_BOARD_DATA_DIR", "data", "directory for the board, users and other files", func(c *Config, v string) error {
+		c.DataDir = v
+		return nil
+	}},
+	{"shutdown_timeout", "SCORE_BOARD_SHUTDOWN_TIMEOUT", "shutdown-timeout", "how long requests get to finish on shutdown", func(c *Config, v string) error {
+		return duration(&c.ShutdownTimeout, v)
+	}},
+	{"tls.cert", "SCORE_BOARD_TLS_CERT", "tls-cert", "TLS certificate file (PEM)", func(c *Config, v string) error {
+		c.TLS.CertFile = v
+		return nil
+	}},
+	{"tls.key", "SCORE_BOARD_TLS_KEY", "tls-key", "TLS private key file (PEM)", func(c *Config, v string) error {
+		c.TLS.KeyFile = v
+		return nil
+	}},
+	{"tls.auto", "SCORE_BOARD_TLS_AUTO", "tls-auto", "serve HTTPS with a certificate from a local CA", func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		c.TLS.Auto = b
+		return nil
+	}},
+	{"tls.hosts", "SCORE_BOARD_TLS_HOSTS", "tls-hosts", "comma-separated extra names or IPs for the local certificate", func(c *Config, v string) error {
+		c.TLS.Hosts = nil
+		for _, h := range strings.Split(v, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				c.TLS.Hosts = append(c.TLS.Hosts, h)
+			}
+		}
+		return nil
+	}},
+	{"tls.redirect", "SCORE_BOARD_TLS_REDIRECT", "tls-redirect", "address for plain HTTP that redirects to HTTPS", func(c *Config, v string) error {
+		c.TLS.Redirect = v
+		return nil
+	}},
+	{"defaults.colors", "SCORE_BOARD_COLORS", "colors", "comma-separated default team colors", func(c *Config, v string) error {
+		var colors []string
+		errs := validate.Errors{}
+		for _, col := range strings.Split(v, ",") {
+			col = strings.TrimSpace(col)
+			if errs.Color("color", col); errs.Any() {
+				return fmt.Errorf("%q %s", col, errs.Get("color"))
+			}
+			colors = append(colors, col)
+		}
+		c.Defaults.Colors = colors
+		return nil
+	}},
+	{"auth.session_ttl", "SCORE_BOARD_SESSION_TTL", "session-ttl", "how long a sign-in lasts", func(c *Config, v string) error {
+		return duration(&c.Auth.SessionTTL, v)
+	}},
+	{"retention.idempotency", "SCORE_BOARD_IDEMPOTENCY_TTL", "idempotency-ttl", "how long repeated requests are recognized", func(c *Config, v string) error {
+		return duration(&c.Retention.Idempotency, v)
+	}},
+	{"retention.sync_log", "SCORE_BOARD_SYNC_LOG_TTL", "sync-log-ttl", "how long offline entries are remembered", func(c *Config, v string) error {
+		return duration(&c.Retention.SyncLog, v)
+	}},
+}
+
+// boolFlags are the settings given as plain switches on the command line.
+var boolFlags = map[string]bool{"tls.auto": true}
+
+func duration(d *time.Duration, v string) error {
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return errors.New("must be a duration like 12h or 30m")
+	}
+	if parsed <= 0 {
+		return errors.New("must be more than zero")
+	}
+	*d = parsed
+	return nil
+}
+
+// Load reads the settings from the config file, environment and the flags
+// at the start of args, and returns the arguments left after the flags.
+// Flags may also follow a "serve" command: "serve -addr :9000".
+func Load(args []string) (*Config, []string, error) {
+	set := flag.NewFlagSet("score-board", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	file := set.String("config", "", "config file (YAML or TOML)")
+	flags := make(map[string]string)
+	for _, s := range settings {
+		record := func(v string) error {
+			flags[s.key] = v
+			return nil
+		}
+		if boolFlags[s.key] {
+			set.BoolFunc(s.flag, s.usage, record)
+		} else {
+			set.Func(s.flag, s.usage, record)
+		}
+	}
+	if err := set.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	rest := set.Args()
+	if len(rest) > 0 && rest[0] == "serve" {
+		if err := set.Parse(rest[1:]); err != nil {
+			return nil, nil, err
+		}
+		rest = append([]string{"serve"}, set.Args()...)
+	}
+
+	// Variables already in the environment win over .env
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf(".env: %w", err)
+	}
+
+	c := Default()
+	if *file == "" {
+		*file = os.Getenv("SCORE_BOARD_CONFIG")
+	}
+	if *file == "" {
+		for _, name := range configFiles {
+			if _, err := os.Stat(name); err == nil {
+				*file = name
+				break
+			}
+		}
+	}
+	var errs []error
+	if *file != "" {
+		values, err := readFile(*file)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.File = *file
+		for _, s := range settings {
+			if v, ok := values[s.key]; ok {
+				delete(values, s.key)
+				if err := s.set(c, v); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %s: %w", *file, s.key, err))
+				}
+			}
+		}
+		for key := range values {
+			errs = append(errs, fmt.Errorf("%s: unknown setting %q", *file, key))
+		}
+	}
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env); ok && v != "" {
+			if err := s.set(c, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, ok := flags[s.key]; ok {
+			if err := s.set(c, v); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", s.flag, err))
+			}
+		}
+	}
+	errs = append(errs, c.validate()...)
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return c, rest, nil
+}
+
+// validate checks the settings that don't stand on their own.
+func (c *Config) validate() []error {
+	var errs []error
+	if _, port, err := net.SplitHostPort(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("addr %q must look like :8080 or 127.0.0.1:8080", c.Addr))
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		errs = append(errs, fmt.Errorf("port %q must be a number up to 65535", port))
+	}
+
+	if c.DataDir == "" {
+		errs = append(errs, errors.New("data_dir can't be empty"))
+	} else if info, err := os.Stat(c.DataDir); err == nil && !info.IsDir() {
+		errs = append(errs, fmt.Errorf("data_dir %q is a file, not a directory", c.DataDir))
+	}
+
+	switch {
+	case c.TLS.Auto && (c.TLS.CertFile != "" || c.TLS.KeyFile != ""):
+		errs = append(errs, errors.New("tls.auto makes its own certificate; leave out tls.cert and tls.key"))
+	case c.TLS.CertFile == "" && c.TLS.KeyFile == "":
+	case c.TLS.CertFile == "" || c.TLS.KeyFile == "":
+		errs = append(errs, errors.New("tls.cert and tls.key have to be set together"))
+	default:
+		for _, f := range []string{c.TLS.CertFile, c.TLS.KeyFile} {
+			if _, err := os.Stat(f); err != nil {
+				errs = append(errs, fmt.Errorf("tls: %w", err))
+			}
+		}
+	}
+
+	if c.TLS.Redirect != "" {
+		if !c.TLS.Enabled() {
+			errs = append(errs, errors.New("tls.redirect needs HTTPS: set tls.auto or tls.cert and tls.key"))
+		}
+		if _, port, err := net.SplitHostPort(c.TLS.Redirect); err != nil {
+			errs = append(errs, fmt.Errorf("tls.redirect %q must look like :80", c.TLS.Redirect))
+		} else if _, addrPort, _ := net.SplitHostPort(c.Addr); port == addrPort {
+			errs = append(errs, fmt.Errorf("tls.redirect and addr can't share port %s", port))
+		}
+	}
+
+	if len(c.Defaults.Colors) == 0 {
+		errs = append(errs, errors.New("defaults.colors needs at least one color"))
+	}
+	return errs
+}
+
+// LocalURL is where the server can be reached from this machine.
+func (c *Config) LocalURL() string {
+	host, port, err := net.SplitHostPort(c.Addr)
+	if err != nil {
+		return "http://localhost:8080"
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	scheme := "http"
+	if c.TLS.Enabled() {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
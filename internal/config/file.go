@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile reads a YAML or TOML config file, picked by its extension, and
+// flattens it to the same strings the environment and flags give, keyed
+// like "tls.cert". Lists become comma-separated.
+func readFile(name string) (map[string]string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]any{}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("%s: config files must end in .yaml, .yml or .toml", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	values := make(map[string]string)
+	if err := flatten(values, "", raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return values, nil
+}
+
+func flatten(out map[string]string, prefix string, m map[string]any) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := prefix + k
+		switch v := m[k].(type) {
+		case map[string]any:
+			if err := flatten(out, key+".", v); err != nil {
+				return err
+			}
+		case []any:
+			parts := make([]string, len(v))
+			for i, item := range v {
+				parts[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(parts, ",")
+		case nil:
+			// "key:" with nothing after it leaves the default alone
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const cookieToken = "cookie-token"
+
+// post runs a POST through Middleware and reports the status and whether
+// the handler behind it ran.
+func post(t *testing.T, cookie, header, field string) (int, bool) {
+	t.Helper()
+	ran := false
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ran = true
+	}))
+
+	form := url.Values{}
+	if field != "" {
+		form.Set(FieldName, field)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != "" {
+		r.AddCookie(&http.Cookie{Name: CookieName, Value: cookie})
+	}
+	if header != "" {
+		r.Header.Set(HeaderName, header)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code, ran
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name                  string
+		cookie, header, field string
+		ok                    bool
+	}{
+		{name: "no token", cookie: cookieToken},
+		{name: "no cookie", header: cookieToken},
+		{name: "wrong header", cookie: cookieToken, header: "other"},
+		{name: "wrong field", cookie: cookieToken, field: "other"},
+		{name: "header", cookie: cookieToken, header: cookieToken, ok: true},
+		{name: "field", cookie: cookieToken, field: cookieToken, ok: true},
+		// The header is checked first, so a wrong one isn't saved by the field
+		{name: "wrong header, right field", cookie: cookieToken, header: "other", field: cookieToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ran := post(t, tt.cookie, tt.header, tt.field)
+			if tt.ok {
+				if !ran || code != http.StatusOK {
+					t.Fatalf("got %d, ran %v; want the request let through", code, ran)
+				}
+				return
+			}
+			if ran || code != http.StatusForbidden {
+				t.Fatalf("got %d, ran %v; want 403 without running the handler", code, ran)
+			}
+		})
+	}
+}
+
+func TestMiddlewareSetsCookie(t *testing.T) {
+	var got string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = Token(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CookieName {
+		t.Fatalf("got cookies %v, want one %s cookie", cookies, CookieName)
+	}
+	if got == "" || got != cookies[0].Value {
+		t.Fatalf("Token() = %q, want the cookie value %q", got, cookies[0].Value)
+	}
+}
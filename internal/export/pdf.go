@@ -0,0 +1,93 @@
+package export
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// WritePDF writes an A4 results sheet: standings with team colors, then
+// the per-game breakdown.
+func WritePDF(w io.Writer, r Results) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	// The Go fonts cover far more than the PDF core fonts' Latin-1.
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+	pdf.SetTitle(r.BoardName+" results", true)
+	pdf.SetMargins(18, 18, 18)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.AddPage()
+
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageW - left - right
+
+	pdf.SetFont("go", "B", 26)
+	pdf.CellFormat(width, 12, r.BoardName, "", 1, "L", false, 0, "")
+	pdf.SetFont("go", "", 11)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(width, 7, r.Subtitle(), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(6)
+
+	// Standings
+	pdf.SetFont("go", "B", 14)
+	pdf.CellFormat(width, 9, "Standings", "", 1, "L", false, 0, "")
+	const rowH = 11
+	for _, row := range r.Rows {
+		x, y := pdf.GetXY()
+		if row.Rank == 1 {
+			pdf.SetFillColor(254, 243, 199)
+			pdf.Rect(x, y, width, rowH, "F")
+		}
+		pdf.SetFillColor(int(row.Color.R), int(row.Color.G), int(row.Color.B))
+		pdf.Rect(x, y, 3, rowH, "F")
+		pdf.SetX(x + 6)
+		pdf.SetFont("go", "B", 16)
+		pdf.CellFormat(12, rowH, strconv.Itoa(row.Rank), "", 0, "L", false, 0, "")
+		pdf.SetFont("go", "", 14)
+		pdf.CellFormat(width-6-12-30, rowH, row.Team, "", 0, "L", false, 0, "")
+		pdf.SetFont("go", "B", 16)
+		pdf.CellFormat(30, rowH, strconv.Itoa(row.Total), "", 1, "R", false, 0, "")
+		pdf.SetDrawColor(220, 220, 220)
+		pdf.Line(x, y+rowH, x+width, y+rowH)
+	}
+
+	if len(r.Games) == 0 {
+		return pdf.Output(w)
+	}
+
+	// Per-game breakdown: one row per game, one column per team.
+	pdf.Ln(8)
+	pdf.SetFont("go", "B", 14)
+	pdf.CellFormat(width, 9, "Results by game", "", 1, "L", false, 0, "")
+	gameW := width * 0.3
+	colW := (width - gameW) / float64(len(r.Rows))
+	pdf.SetFont("go", "B", 10)
+	pdf.CellFormat(gameW, 8, "Game", "B", 0, "L", false, 0, "")
+	for _, row := range r.Rows {
+		pdf.SetTextColor(int(row.Color.R), int(row.Color.G), int(row.Color.B))
+		pdf.CellFormat(colW, 8, row.Team, "B", 0, "R", false, 0, "")
+	}
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(-1)
+	pdf.SetFont("go", "", 10)
+	for i, name := range r.Games {
+		pdf.CellFormat(gameW, 7, name, "", 0, "L", false, 0, "")
+		for _, row := range r.Rows {
+			pdf.CellFormat(colW, 7, strconv.Itoa(row.Games[i]), "", 0, "R", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("go", "B", 10)
+	pdf.CellFormat(gameW, 8, "Total", "T", 0, "L", false, 0, "")
+	for _, row := range r.Rows {
+		pdf.CellFormat(colW, 8, strconv.Itoa(row.Total), "T", 0, "R", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	return pdf.Output(w)
+}
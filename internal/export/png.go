@@ -0,0 +1,149 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Image layout, in pixels.
+const (
+	imgWidth   = 1200
+	imgPadding = 60
+	imgRowH    = 84
+	imgGameRow = 44
+)
+
+var (
+	imgBackground = color.RGBA{15, 16, 24, 255}
+	imgText       = color.RGBA{255, 255, 255, 255}
+	imgMuted      = color.RGBA{160, 164, 178, 255}
+	imgAccent     = color.RGBA{250, 204, 21, 255}
+	imgRow        = color.RGBA{28, 30, 42, 255}
+	imgWinnerRow  = color.RGBA{58, 52, 22, 255}
+)
+
+// faces holds the font sizes used on the image.
+type faces struct {
+	title, subtitle, rank, team, total, heading, small font.Face
+}
+
+func loadFaces() (faces, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return faces{}, err
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return faces{}, err
+	}
+	var fs faces
+	for _, f := range []struct {
+		dst  *font.Face
+		font *opentype.Font
+		size float64
+	}{
+		{&fs.title, bold, 56},
+		{&fs.subtitle, regular, 24},
+		{&fs.rank, bold, 40},
+		{&fs.team, bold, 34},
+		{&fs.total, bold, 44},
+		{&fs.heading, bold, 28},
+		{&fs.small, regular, 22},
+	} {
+		face, err := opentype.NewFace(f.font, &opentype.FaceOptions{Size: f.size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return faces{}, err
+		}
+		*f.dst = face
+	}
+	return fs, nil
+}
+
+// WritePNG writes the results as a PNG sized for sharing on social media.
+func WritePNG(w io.Writer, r Results) error {
+	fs, err := loadFaces()
+	if err != nil {
+		return err
+	}
+
+	height := imgPadding + 150 + len(r.Rows)*(imgRowH+12)
+	if len(r.Games) > 0 {
+		height += 90 + (len(r.Games)+1)*imgGameRow
+	}
+	height += imgPadding
+	img := image.NewRGBA(image.Rect(0, 0, imgWidth, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(imgBackground), image.Point{}, draw.Src)
+
+	y := imgPadding + 50
+	text(img, fs.title, imgText, imgPadding, y, r.BoardName)
+	y += 44
+	text(img, fs.subtitle, imgMuted, imgPadding, y, r.Subtitle())
+	y += 56
+
+	right := imgWidth - imgPadding
+	for _, row := range r.Rows {
+		bg := imgRow
+		if row.Rank == 1 {
+			bg = imgWinnerRow
+		}
+		fill(img, imgPadding, y, right, y+imgRowH, bg)
+		fill(img, imgPadding, y, imgPadding+14, y+imgRowH, row.Color)
+		base := y + imgRowH/2 + 14
+		rankColor := imgText
+		if row.Rank == 1 {
+			rankColor = imgAccent
+		}
+		text(img, fs.rank, rankColor, imgPadding+36, base, strconv.Itoa(row.Rank))
+		text(img, fs.team, imgText, imgPadding+110, base, row.Team)
+		textRight(img, fs.total, imgText, right-24, base, strconv.Itoa(row.Total))
+		y += imgRowH + 12
+	}
+
+	if len(r.Games) > 0 {
+		y += 50
+		text(img, fs.heading, imgText, imgPadding, y, "Results by game")
+		y += 20
+		gameW := (right - imgPadding) * 3 / 10
+		colW := (right - imgPadding - gameW) / len(r.Rows)
+		y += imgGameRow
+		for j, row := range r.Rows {
+			textRight(img, fs.small, row.Color, imgPadding+gameW+(j+1)*colW, y-12, row.Team)
+		}
+		fill(img, imgPadding, y-4, right, y-2, imgMuted)
+		for i, name := range r.Games {
+			y += imgGameRow
+			text(img, fs.small, imgText, imgPadding, y-12, name)
+			for j, row := range r.Rows {
+				textRight(img, fs.small, imgText, imgPadding+gameW+(j+1)*colW, y-12, strconv.Itoa(row.Games[i]))
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func fill(img draw.Image, x0, y0, x1, y1 int, c color.Color) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// text draws s with its baseline at y.
+func text(img draw.Image, face font.Face, c color.Color, x, y int, s string) {
+	d := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+// textRight draws s right-aligned to x.
+func textRight(img draw.Image, face font.Face, c color.Color, x, y int, s string) {
+	width := font.MeasureString(face, s).Ceil()
+	text(img, face, c, x-width, y, s)
+}
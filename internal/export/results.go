@@ -0,0 +1,103 @@
+// Package export renders a board's final results as a printable PDF or a
+// PNG image for sharing. Everything is pure Go (fonts included), so it
+// works without network access or system libraries.
+package export
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+// Results is what goes on the results sheet.
+type Results struct {
+	BoardName string
+	Date      time.Time
+	Final     bool // finished event, not a snapshot of a live board
+	Games     []string
+	Rows      []Row // in standings order
+}
+
+// Row is one team's line on the sheet.
+type Row struct {
+	Rank  int
+	Team  string
+	Color color.RGBA
+	Total int
+	Games []int // score per game, same order as Results.Games
+}
+
+// FromBoard builds the results for a board. A finished board is dated by
+// when it finished; a live one by now.
+func FromBoard(b *store.ScoreBoard, now time.Time) Results {
+	r := Results{BoardName: b.BoardName, Date: now}
+	if b.Finished() {
+		r.Date, r.Final = b.FinishedAt, true
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			if g.GameName != "" && !seen[g.GameName] {
+				seen[g.GameName] = true
+				r.Games = append(r.Games, g.GameName)
+			}
+		}
+	}
+
+	teams := make(map[string]*store.Team)
+	for _, t := range b.Teams {
+		if t != nil {
+			teams[t.TeamName] = t
+		}
+	}
+	for _, s := range b.Standings() {
+		t := teams[s.Team]
+		row := Row{Rank: s.Rank, Team: s.Team, Color: ParseHex(t.TeamColor["color"]), Total: s.Total}
+		for _, name := range r.Games {
+			score := 0
+			for _, g := range t.Games {
+				if g.GameName == name {
+					score += g.Score()
+				}
+			}
+			row.Games = append(row.Games, score)
+		}
+		r.Rows = append(r.Rows, row)
+	}
+	return r
+}
+
+// FromArchive builds the results for an archived event.
+func FromArchive(a store.ArchivedBoard) Results {
+	b := *a.Board
+	b.FinishedAt = a.FinishedAt
+	return FromBoard(&b, a.FinishedAt)
+}
+
+// Subtitle is the line under the board name.
+func (r Results) Subtitle() string {
+	if r.Final {
+		return "Final results · " + r.Date.Local().Format("Monday, January 2, 2006")
+	}
+	return "Standings as of " + r.Date.Local().Format("January 2, 2006 15:04")
+}
+
+// ParseHex reads a "#RRGGBB" (or "#RGB") color, falling back to white.
+func ParseHex(s string) color.RGBA {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 6 || err != nil {
+		return color.RGBA{255, 255, 255, 255}
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}
+}
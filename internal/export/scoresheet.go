@@ -0,0 +1,85 @@
+package export
+
+import (
+	"image/color"
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Scoresheet is a paper form for one game: a row per team and an empty box
+// per round, filled in by hand when the venue Wi-Fi is down.
+type Scoresheet struct {
+	BoardName string
+	Game      string
+	Scoring   string // how the game is scored, printed as a reminder
+	Date      time.Time
+	Teams     []SheetTeam
+	Rounds    []string
+}
+
+// SheetTeam is one team's row on a scoresheet.
+type SheetTeam struct {
+	Name  string
+	Color color.RGBA
+}
+
+// WriteScoresheetPDF writes the scoresheet as a landscape A4 page.
+func WriteScoresheetPDF(w io.Writer, s Scoresheet) error {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+	pdf.SetTitle(s.Game+" scoresheet", true)
+	pdf.SetMargins(14, 14, 14)
+	pdf.SetAutoPageBreak(true, 14)
+	pdf.AddPage()
+
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageW - left - right
+
+	pdf.SetFont("go", "B", 22)
+	pdf.CellFormat(width, 10, s.Game, "", 1, "L", false, 0, "")
+	pdf.SetFont("go", "", 11)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.CellFormat(width, 6, s.BoardName+" · "+s.Date.Local().Format("Monday, January 2, 2006")+" · "+s.Scoring, "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(5)
+
+	// Team column, one box per round, then the total.
+	teamW := 55.0
+	totalW := 24.0
+	roundW := (width - teamW - totalW) / float64(max(len(s.Rounds), 1))
+	const headH, rowH = 9.0, 16.0
+
+	pdf.SetFont("go", "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(teamW, headH, "Team", "1", 0, "L", true, 0, "")
+	for _, rn := range s.Rounds {
+		pdf.CellFormat(roundW, headH, "Round "+rn, "1", 0, "C", true, 0, "")
+	}
+	pdf.CellFormat(totalW, headH, "Total", "1", 1, "C", true, 0, "")
+
+	pdf.SetFont("go", "B", 12)
+	for _, t := range s.Teams {
+		x, y := pdf.GetXY()
+		pdf.CellFormat(teamW, rowH, "   "+t.Name, "1", 0, "L", false, 0, "")
+		pdf.SetFillColor(int(t.Color.R), int(t.Color.G), int(t.Color.B))
+		pdf.Rect(x+0.5, y+0.5, 2.5, rowH-1, "F")
+		for range s.Rounds {
+			pdf.CellFormat(roundW, rowH, "", "1", 0, "C", false, 0, "")
+		}
+		pdf.CellFormat(totalW, rowH, "", "1", 1, "C", false, 0, "")
+	}
+
+	pdf.Ln(14)
+	pdf.SetFont("go", "", 11)
+	half := width / 2
+	pdf.CellFormat(half, 8, "Scorekeeper: ______________________________", "", 0, "L", false, 0, "")
+	pdf.CellFormat(half, 8, "Entered by: ______________________________", "", 1, "L", false, 0, "")
+
+	return pdf.Output(w)
+}
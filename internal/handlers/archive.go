@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+)
+
+// RequireOpenBoard rejects changes to a finished board. It stays read-only
+// until it is reset for the next event.
+func (h *ScoreBoardHandler) RequireOpenBoard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.current().Finished() {
+			http.Error(w, "this event is finished; reset the board to start a new one", http.StatusConflict)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PostFinishBoard freezes the board and stores its final standings in the
+// archive.
+func (h *ScoreBoardHandler) PostFinishBoard(w http.ResponseWriter, r *http.Request) {
+	b := h.edit()
+	if b == nil || len(b.Teams) == 0 {
+		http.Error(w, "no board to finish", http.StatusBadRequest)
+		return
+	}
+	if b.Finished() {
+		http.Redirect(w, r, "/archive/"+b.ArchiveID, http.StatusSeeOther)
+		return
+	}
+	// b is a copy, so the live board only ends up finished once it's in the
+	// archive and saved; if either fails, it carries on as it was.
+	b.Finish(time.Now())
+	entry, err := h.archive.Add(b)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b.ArchiveID = entry.ID
+	if err := h.save(b); err != nil {
+		// Don't leave an archived copy of an event that isn't finished
+		if rerr := h.archive.Remove(entry.ID); rerr != nil {
+			log.Printf("finish: taking back archive entry %s: %v", entry.ID, rerr)
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.publishTimers()
+	http.Redirect(w, r, "/archive/"+entry.ID, http.StatusSeeOther)
+}
+
+// GetArchive lists past events, newest first.
+func (h *ScoreBoardHandler) GetArchive(w http.ResponseWriter, r *http.Request) {
+	c := templates.Archive(h.archive.List())
+	if err := templates.Layout(c, "Archive").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetArchivedBoard shows the final results of one past event.
+func (h *ScoreBoardHandler) GetArchivedBoard(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.archive.Get(chi.URLParam(r, "id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	c := templates.ArchivedBoard(entry)
+	if err := templates.Layout(c, entry.BoardName).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+func TestFinishLeavesBoardOpenWhenArchiveFails(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	// A directory where archive.json should go makes every archive write fail
+	if err := os.Mkdir(filepath.Join(dir, "archive.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.Board.PostFinishBoard(w, httptest.NewRequest(http.MethodPost, "/settings/finish", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d, want 500", w.Code)
+	}
+	if b := h.Board.current(); b.Finished() || b.ArchiveID != "" {
+		t.Fatalf("board finished at %v with archive %q; want it still open", b.FinishedAt, b.ArchiveID)
+	}
+	if len(h.Board.archive.List()) != 0 {
+		t.Fatal("archive kept an entry it couldn't save")
+	}
+}
+
+func TestResetSavesEmptyBoard(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	w := httptest.NewRecorder()
+	h.Board.PostResetBoard(w, httptest.NewRequest(http.MethodPost, "/settings/reset", nil))
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("got %d, want 303", w.Code)
+	}
+	saved := store.LoadBoard(filepath.Join(dir, store.DBFilename)).GetBoard()
+	if len(saved.Teams) != 0 || saved.Version != h.Board.current().Version {
+		t.Fatalf("db.json has %d teams at version %d; want the empty board at %d", len(saved.Teams), saved.Version, h.Board.current().Version)
+	}
+	if len(h.Board.archive.List()) != 1 {
+		t.Fatal("the old board wasn't archived")
+	}
+}
+
+func TestResetKeepsBoardWhenSaveFails(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	// A directory where db.json should go makes saving the board fail
+	db := filepath.Join(dir, store.DBFilename)
+	if err := os.Remove(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(db, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.Board.PostResetBoard(w, httptest.NewRequest(http.MethodPost, "/settings/reset", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d, want 500", w.Code)
+	}
+	if n := len(h.Board.current().Teams); n != 2 {
+		t.Fatalf("board has %d teams, want the 2 it had", n)
+	}
+	if len(h.Board.archive.List()) != 0 {
+		t.Fatal("archive kept a board that was never cleared")
+	}
+}
@@ -0,0 +1,184 @@
+// Package handlers
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+const minPasswordLen = 8
+
+type AuthHandler struct {
+	auth *auth.Manager
+}
+
+// NewAuthHandler creates an AuthHandler bound to the auth manager.
+func NewAuthHandler(am *auth.Manager) *AuthHandler {
+	return &AuthHandler{
+		auth: am,
+	}
+}
+
+// GetLogin renders the login form.
+func (h *AuthHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
+	h.renderLogin(w, r, r.URL.Query().Get("next"), "")
+}
+
+func (h *AuthHandler) renderLogin(w http.ResponseWriter, r *http.Request, next, errMsg string) {
+	c := templates.Login(next, errMsg)
+	if err := templates.Layout(c, "Login").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostLogin checks the credentials and starts a session.
+func (h *AuthHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	next := r.FormValue("next")
+	u, err := h.auth.Users.Authenticate(r.FormValue("username"), r.FormValue("password"))
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		h.renderLogin(w, r, next, err.Error())
+		return
+	}
+	h.auth.Login(w, r, u)
+	http.Redirect(w, r, auth.SafeNext(next), http.StatusSeeOther)
+}
+
+// PostLogout ends the session and goes back home.
+func (h *AuthHandler) PostLogout(w http.ResponseWriter, r *http.Request) {
+	h.auth.Logout(w, r)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// GetSetup shows the first-run form for creating the admin account.
+func (h *AuthHandler) GetSetup(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.Users.Empty() {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	h.renderSetup(w, r, "")
+}
+
+func (h *AuthHandler) renderSetup(w http.ResponseWriter, r *http.Request, errMsg string) {
+	c := templates.Setup(errMsg)
+	if err := templates.Layout(c, "Setup").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostSetup creates the first admin and signs them in.
+// Only works while there are no accounts at all.
+func (h *AuthHandler) PostSetup(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.Users.Empty() {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	username := strings.TrimSpace(r.FormValue("username"))
+	password := r.FormValue("password")
+	if msg := checkCredentials(username, password, r.FormValue("confirm")); msg != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderSetup(w, r, msg)
+		return
+	}
+	if err := h.auth.Users.CreateFirstAdmin(username, password); err != nil {
+		if errors.Is(err, auth.ErrSetupDone) {
+			// Someone else finished setup first
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.auth.Login(w, r, h.auth.Users.Get(username))
+	http.Redirect(w, r, "/board", http.StatusSeeOther)
+}
+
+// checkCredentials returns a user-facing message if the new account is unusable.
+func checkCredentials(username, password, confirm string) string {
+	errs := validate.Errors{}
+	errs.Name("username", username, validate.MaxUsername)
+	if errs.Any() {
+		return "username " + errs.Get("username")
+	}
+	if len(password) < minPasswordLen {
+		return "password must be at least 8 characters"
+	}
+	if password != confirm {
+		return "passwords do not match"
+	}
+	return ""
+}
+
+// GetUsers lists accounts and shows a form to add one.
+func (h *AuthHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	h.renderUsers(w, r, "")
+}
+
+func (h *AuthHandler) renderUsers(w http.ResponseWriter, r *http.Request, errMsg string) {
+	c := templates.Users(h.auth.Users.List(), errMsg)
+	if err := templates.Layout(c, "Users").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostUsers creates an account with the chosen role.
+func (h *AuthHandler) PostUsers(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	username := strings.TrimSpace(r.FormValue("username"))
+	password := r.FormValue("password")
+	if msg := checkCredentials(username, password, r.FormValue("confirm")); msg != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderUsers(w, r, msg)
+		return
+	}
+	if err := h.auth.Users.Add(username, password, auth.Role(r.FormValue("role"))); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderUsers(w, r, err.Error())
+		return
+	}
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// PostDeleteUser removes an account. The last admin can't be removed,
+// otherwise nobody could manage the board anymore.
+func (h *AuthHandler) PostDeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	username := strings.TrimSpace(r.FormValue("username"))
+	target := h.auth.Users.Get(username)
+	if target == nil {
+		http.Redirect(w, r, "/users", http.StatusSeeOther)
+		return
+	}
+	if target.Role == auth.RoleAdmin && h.auth.Users.CountRole(auth.RoleAdmin) <= 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderUsers(w, r, "cannot remove the last admin")
+		return
+	}
+	if err := h.auth.Users.Remove(username); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
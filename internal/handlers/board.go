@@ -2,32 +2,216 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/auth"
 	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/live"
 	"github.com/mrjxtr-dev/score-board/internal/store"
 	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
 )
 
 type ScoreBoardHandler struct {
-	store store.Database
+	// board is the board as last saved. It's never changed in place, so
+	// pages can read it while a write is going on; see edit.
+	board   atomic.Pointer[store.ScoreBoard]
+	cfg     *config.Config
+	dataDir string
+	// linkHosts are the names team links may use; see teamLinkURL.
+	linkHosts map[string]bool
+	tokens    *auth.TeamTokens
+	live      *live.Broker
+	boards    *store.Templates
+	archive   *store.Archive
+	syncLog   *store.SyncLog
+	history   *store.History
+
+	// writeMu lets one request change data at a time; see Writes.
+	writeMu sync.Mutex
+
+	// standings is the snapshot last sent to live views, used to work out
+	// what changed on the next save.
+	mu        sync.Mutex
+	standings []store.Standing
 }
 
-func NewScoreBoardHandler(db store.Database) *ScoreBoardHandler {
-	return &ScoreBoardHandler{
-		store: db,
+func NewScoreBoardHandler(db store.Database, cfg *config.Config, tokens *auth.TeamTokens, lv *live.Broker, boards *store.Templates, archive *store.Archive, syncLog *store.SyncLog) *ScoreBoardHandler {
+	history := store.NewHistory()
+	history.Remember(db.GetBoard())
+	h := &ScoreBoardHandler{
+		cfg:       cfg,
+		dataDir:   cfg.DataDir,
+		linkHosts: linkHosts(cfg),
+		tokens:    tokens,
+		live:      lv,
+		boards:    boards,
+		archive:   archive,
+		syncLog:   syncLog,
+		history:   history,
+		standings: db.GetBoard().Standings(),
+	}
+	h.board.Store(db.GetBoard())
+	return h
+}
+
+// current returns the board to read from. Don't change it; use edit.
+func (h *ScoreBoardHandler) current() *store.ScoreBoard {
+	return h.board.Load()
+}
+
+// edit returns a copy of the board to change and pass to save. Until then
+// nobody else sees the changes, and a failed save leaves the board as it was.
+func (h *ScoreBoardHandler) edit() *store.ScoreBoard {
+	return h.current().Clone()
+}
+
+// save bumps the board version, stamps the games that changed with it and
+// persists the board.
+func (h *ScoreBoardHandler) save(b *store.ScoreBoard) error {
+	b.Version++
+	b.MarkChanged(h.current())
+	if err := h.persist(b); err != nil {
+		return err
+	}
+	h.history.Remember(b)
+	return nil
+}
+
+// persist writes the board without touching its version, makes it the
+// current board and tells live views to refresh. Timers use it directly:
+// starting a clock shouldn't make anyone's open form stale. If the write
+// fails the caller answers with an error rather than pretending it worked.
+func (h *ScoreBoardHandler) persist(b *store.ScoreBoard) error {
+	if err := os.MkdirAll(h.dataDir, 0755); err != nil {
+		return fmt.Errorf("saving the board: %w", err)
+	}
+	if err := b.SaveToJSON(filepath.Join(h.dataDir, store.DBFilename)); err != nil {
+		return fmt.Errorf("saving the board: %w", err)
+	}
+	h.board.Store(b)
+	h.publish()
+	return nil
+}
+
+// Writes runs requests that change the board one at a time. Handlers copy
+// the board, change it and save it; two at once would each save their own
+// copy and one of the changes would be lost. The body is read first so a
+// slow client doesn't hold everyone else up. Only board routes use it, so
+// signing in or managing users never waits on a score.
+func (h *ScoreBoardHandler) Writes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Same cap ParseForm puts on form bodies
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 10<<20))
+		if err != nil {
+			http.Error(w, "request body too large or cut off", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		h.writeMu.Lock()
+		defer h.writeMu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Flush writes the board to disk as it is in memory. The server calls it
+// on the way out, once requests have stopped; one still running past the
+// shutdown timeout is waited for.
+func (h *ScoreBoardHandler) Flush() error {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if err := os.MkdirAll(h.dataDir, 0755); err != nil {
+		return err
 	}
+	return h.current().SaveToJSON(filepath.Join(h.dataDir, store.DBFilename))
+}
+
+// fresh reports whether a form was rendered from the current version of the
+// board. Forms and scripts that don't send a version always pass. A stale
+// one gets a 409 page listing what changed in the meantime, with a link
+// back to where to try again.
+func (h *ScoreBoardHandler) fresh(w http.ResponseWriter, r *http.Request, b *store.ScoreBoard, back string) bool {
+	return h.freshSince(w, r, b, b.Version, back)
+}
+
+// freshGame is fresh for a form that edits one team's game. Scores go in
+// for every team at once during an event, so only a change to that game
+// makes the form stale.
+func (h *ScoreBoardHandler) freshGame(w http.ResponseWriter, r *http.Request, b *store.ScoreBoard, g *store.Game, back string) bool {
+	return h.freshSince(w, r, b, g.Changed, back)
+}
+
+// freshSince passes a form rendered from any version from since on.
+func (h *ScoreBoardHandler) freshSince(w http.ResponseWriter, r *http.Request, b *store.ScoreBoard, since int, back string) bool {
+	raw := strings.TrimSpace(r.FormValue("version"))
+	if raw == "" {
+		return true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return false
+	}
+	if v >= since && v <= b.Version {
+		return true
+	}
+
+	page := templates.ConflictPage{Back: back, Since: b.Version - v}
+	if old, ok := h.history.Get(v); ok && v < b.Version {
+		page.Changes = store.Changes(old, b)
+		page.Known = true
+	}
+	if isHTMX(r) {
+		// Show the whole conflict page, not just where the fragment goes
+		w.Header().Set("HX-Retarget", "body")
+		w.Header().Set("HX-Reswap", "innerHTML")
+	}
+	w.WriteHeader(http.StatusConflict)
+	if err := templates.Layout(templates.Conflict(page), "Board Changed").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return false
+}
+
+// publish notifies live views that the board changed. Score pages get only
+// the per-team deltas since the last publish so they can animate them; a
+// change in the set of teams tells them to reload instead.
+func (h *ScoreBoardHandler) publish() {
+	next := h.current().Standings()
+
+	h.mu.Lock()
+	prev := h.standings
+	h.standings = next
+	h.mu.Unlock()
+
+	if !store.SameTeams(prev, next) {
+		h.live.Publish(live.Event{Name: "teams", Data: "changed"})
+	} else if deltas := store.DiffStandings(prev, next); len(deltas) > 0 {
+		if data, err := json.Marshal(deltas); err == nil {
+			h.live.Publish(live.Event{Name: "scores", Data: string(data)})
+		}
+	}
+	h.live.Publish(live.Event{Name: "board", Data: "changed"})
 }
 
 // GetScoreBoard renders the board page or redirects to creation if empty.
 func (h *ScoreBoardHandler) GetScoreBoard(w http.ResponseWriter, r *http.Request) {
-	b := h.store.GetBoard()
+	b := h.current()
 	if b == nil || len(b.Teams) == 0 || b.BoardName == "" {
 		http.Redirect(w, r, "/board/new", http.StatusSeeOther)
 		return
@@ -43,7 +227,11 @@ func (h *ScoreBoardHandler) GetScoreBoard(w http.ResponseWriter, r *http.Request
 
 // GetNewBoard shows the form for creating a scoreboard.
 func (h *ScoreBoardHandler) GetNewBoard(w http.ResponseWriter, r *http.Request) {
-	c := templates.CreateBoard()
+	h.renderNewBoard(w, r, templates.BoardForm{})
+}
+
+func (h *ScoreBoardHandler) renderNewBoard(w http.ResponseWriter, r *http.Request, f templates.BoardForm) {
+	c := templates.CreateBoard(f, h.boards.List())
 	err := templates.Layout(c, "Create Board").Render(r.Context(), w)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -51,148 +239,198 @@ func (h *ScoreBoardHandler) GetNewBoard(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// colorForIndex picks a default color based on index 0..3.
+// colorForIndex picks the default color for the team in slot i (0-based).
 func colorForIndex(i int) map[string]string {
-	order := []string{"pink", "red", "blue", "yellow"}
-	if i < 0 || i >= len(order) {
-		return map[string]string{"color": "#FFFFFF"}
-	}
-	name := order[i]
-	hex := config.DefaulColors[name]
-	return map[string]string{"color": hex}
+	return map[string]string{"color": templates.DefaultColorHex(i + 1)}
 }
 
-// PostNewBoard handles form submission to create a scoreboard.
-func (h *ScoreBoardHandler) PostNewBoard(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "invalid form", http.StatusBadRequest)
-		return
-	}
-
-	boardName := r.FormValue("board_name")
-	if boardName == "" {
-		http.Error(w, "board name required", http.StatusBadRequest)
-		return
-	}
-
-	teams := make([]*store.Team, 0, 4)
-	for i := 1; i <= 4; i++ {
-		idx := strconv.Itoa(i)
-		name := r.FormValue("team_name_" + idx)
-		membersRaw := r.FormValue("team_members_" + idx)
-		var members []string
-		if strings.TrimSpace(membersRaw) != "" {
-			parts := strings.Split(membersRaw, ",")
-			for _, p := range parts {
-				trim := strings.TrimSpace(p)
-				if trim != "" {
-					members = append(members, trim)
-				}
-			}
-		}
+// parseBoardForm reads and validates the board name and team slots shared
+// by the create and settings forms. Teams are only returned when the form
+// has no errors.
+func parseBoardForm(r *http.Request) (templates.BoardForm, []*store.Team) {
+	f := templates.BoardForm{
+		BoardName: strings.TrimSpace(r.FormValue("board_name")),
+		Errors:    validate.Errors{},
+	}
+	f.Errors.Name("board_name", f.BoardName, validate.MaxBoardName)
+
+	teams := make([]*store.Team, 0, templates.MaxTeams)
+	names := make([]string, 0, templates.MaxTeams)
+	for i := 1; i <= templates.MaxTeams; i++ {
+		nameField := templates.TeamField("team_name_", i)
+		membersField := templates.TeamField("team_members_", i)
+		name := strings.TrimSpace(r.FormValue(nameField))
+		membersRaw := r.FormValue(membersField)
+		f.Teams[i-1] = templates.TeamForm{Name: name, Members: membersRaw}
+
+		members := f.Errors.Members(membersField, membersRaw)
 		if name == "" && len(members) == 0 {
 			continue
 		}
 		if name == "" {
+			f.Errors.Add(nameField, "team name required when members are listed")
 			continue
 		}
+		f.Errors.Name(nameField, name, validate.MaxTeamName)
+		f.Errors.Unique(nameField, name, names)
+		names = append(names, name)
+
 		teams = append(teams, &store.Team{
 			TeamName:  name,
 			TeamColor: colorForIndex(len(teams)),
 			Members:   members,
 		})
 	}
+	if f.Errors.Any() {
+		return f, nil
+	}
+	return f, teams
+}
 
-	b := store.NewBoard(boardName)
+// PostNewBoard handles form submission to create a scoreboard.
+func (h *ScoreBoardHandler) PostNewBoard(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	// A template fills in any empty team slots up to its team count so
+	// the board can be spun up from just a name.
+	var tpl store.BoardTemplate
+	if name := strings.TrimSpace(r.FormValue("template")); name != "" {
+		var ok bool
+		if tpl, ok = h.boards.Get(name); !ok {
+			http.Error(w, "template not found", http.StatusBadRequest)
+			return
+		}
+		for i := 1; i <= tpl.TeamCount && i <= templates.MaxTeams; i++ {
+			field := templates.TeamField("team_name_", i)
+			if strings.TrimSpace(r.Form.Get(field)) == "" {
+				r.Form.Set(field, "Team "+strconv.Itoa(i))
+			}
+		}
+	}
+
+	f, teams := parseBoardForm(r)
+	f.Template = tpl.Name
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderNewBoard(w, r, f)
+		return
+	}
+
+	b := store.NewBoard(f.BoardName)
+	b.Version = h.current().Version // keep counting so old forms stay stale
 	for _, t := range teams {
 		b.AddTeam(t)
 	}
+	tpl.Apply(b)
 
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	// reload store so next /board reflects saved data
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	http.Redirect(w, r, "/board", http.StatusSeeOther)
 }
 
 // GetSettings shows a simple settings screen to edit the board or reset it.
 func (h *ScoreBoardHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
-	b := h.store.GetBoard()
-	c := templates.Settings(b)
+	h.renderSettings(w, r, templates.BoardFormFrom(h.current()))
+}
+
+func (h *ScoreBoardHandler) renderSettings(w http.ResponseWriter, r *http.Request, f templates.BoardForm) {
+	b := h.current()
+	c := templates.Settings(b, f, h.teamLinks(r))
 	if err := templates.Layout(c, "Settings").Render(r.Context(), w); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
-// PostSettings updates the board name and teams in one go.
-// It rebuilds the board from the posted fields and saves it.
+// PostSettings updates the board name and teams in one go. Team slot i on
+// the form is the i-th team on the board, so a renamed team keeps its
+// color and scores; new teams start with every game and no rounds.
 func (h *ScoreBoardHandler) PostSettings(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form", http.StatusBadRequest)
 		return
 	}
+	b := h.edit()
+	if !h.fresh(w, r, b, "/settings") {
+		return
+	}
 
-	boardName := strings.TrimSpace(r.FormValue("board_name"))
-	if boardName == "" {
-		boardName = "Untitled Board"
+	f, teams := parseBoardForm(r)
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderSettings(w, r, f)
+		return
 	}
 
-	teams := make([]*store.Team, 0, 4)
-	for i := 1; i <= 4; i++ {
-		idx := strconv.Itoa(i)
-		name := strings.TrimSpace(r.FormValue("team_name_" + idx))
-		membersRaw := r.FormValue("team_members_" + idx)
-		var members []string
-		if strings.TrimSpace(membersRaw) != "" {
-			parts := strings.Split(membersRaw, ",")
-			for _, p := range parts {
-				trim := strings.TrimSpace(p)
-				if trim != "" {
-					members = append(members, trim)
-				}
-			}
-		}
-		if name == "" && len(members) == 0 {
+	for i := 1; i <= templates.MaxTeams; i++ {
+		name := strings.TrimSpace(r.FormValue(templates.TeamField("team_name_", i)))
+		idx := slices.IndexFunc(teams, func(t *store.Team) bool { return t.TeamName == name })
+		if name == "" || idx < 0 {
 			continue
 		}
-
-		teams = append(teams, &store.Team{
-			TeamName:  name,
-			TeamColor: colorForIndex(len(teams)),
-			Members:   members,
-		})
+		t := teams[idx]
+		if i <= len(b.Teams) && b.Teams[i-1] != nil {
+			t.TeamColor = b.Teams[i-1].TeamColor
+			t.Games = b.Teams[i-1].Games
+		} else {
+			t.Games = b.BlankGames()
+		}
 	}
 
-	// Build new board and persist
-	nb := store.NewBoard(boardName)
-	for _, t := range teams {
-		nb.AddTeam(t)
+	b.BoardName = f.BoardName
+	b.Teams = teams
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	_ = os.MkdirAll("./data", 0755)
-	_ = nb.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
-
 	http.Redirect(w, r, "/board", http.StatusSeeOther)
 }
 
-// PostResetBoard wipes the saved board and sends you to create a new one.
+// PostResetBoard clears the board and sends you to create a new one.
+// A board that wasn't finished yet is archived first so nothing is lost.
 func (h *ScoreBoardHandler) PostResetBoard(w http.ResponseWriter, r *http.Request) {
-	// Best-effort delete; if it's not there that's fine.
-	_ = os.Remove(filepath.Join("./data", "db.json"))
+	b := h.current()
+	var archived string
+	if len(b.Teams) > 0 && !b.Finished() {
+		entry, err := h.archive.Add(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		archived = entry.ID
+	}
 
-	// Reset in-memory board too so navigation doesn't show stale data.
-	h.store = store.NewBoard("")
+	nb := store.NewBoard("")
+	nb.Version = b.Version // keep counting so old forms stay stale
+	if err := h.save(nb); err != nil {
+		// The board is still here, so it shouldn't be in the archive too
+		if archived != "" {
+			if rerr := h.archive.Remove(archived); rerr != nil {
+				log.Printf("reset: taking back archive entry %s: %v", archived, rerr)
+			}
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	http.Redirect(w, r, "/board/new", http.StatusSeeOther)
 }
 
 // GetGames shows the games page to list and add games.
 func (h *ScoreBoardHandler) GetGames(w http.ResponseWriter, r *http.Request) {
-	b := h.store.GetBoard()
-	c := templates.Games(b)
+	h.renderGames(w, r, templates.GamesForm{})
+}
+
+func (h *ScoreBoardHandler) renderGames(w http.ResponseWriter, r *http.Request, f templates.GamesForm) {
+	b := h.current()
+	c := templates.Games(b, f)
 	if err := templates.Layout(c, "Games").Render(r.Context(), w); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -206,11 +444,15 @@ func (h *ScoreBoardHandler) PostGames(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	gameName := strings.TrimSpace(r.FormValue("game_name"))
-	if gameName == "" {
-		http.Error(w, "game name required", http.StatusBadRequest)
+	b := h.edit()
+	f := templates.GamesForm{GameName: gameName, Errors: validate.Errors{}}
+	f.Errors.Name("game_name", gameName, validate.MaxGameName)
+	f.Errors.Unique("game_name", gameName, templates.UniqueGameNames(b))
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderGames(w, r, f)
 		return
 	}
-	b := h.store.GetBoard()
 	for _, t := range b.Teams {
 		if t == nil {
 			continue
@@ -226,9 +468,10 @@ func (h *ScoreBoardHandler) PostGames(w http.ResponseWriter, r *http.Request) {
 			t.Games = append(t.Games, store.Game{GameName: gameName, Rounds: make(map[string]int)})
 		}
 	}
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	http.Redirect(w, r, "/games", http.StatusSeeOther)
 }
 
@@ -240,11 +483,28 @@ func (h *ScoreBoardHandler) PostRenameGame(w http.ResponseWriter, r *http.Reques
 	}
 	oldName := strings.TrimSpace(r.FormValue("old_name"))
 	newName := strings.TrimSpace(r.FormValue("new_name"))
-	if oldName == "" || newName == "" {
-		http.Error(w, "old and new names required", http.StatusBadRequest)
+	if oldName == "" {
+		http.Error(w, "old name required", http.StatusBadRequest)
+		return
+	}
+	b := h.edit()
+	if !h.fresh(w, r, b, "/games") {
+		return
+	}
+	f := templates.GamesForm{RenameOld: oldName, RenameNew: newName, Errors: validate.Errors{}}
+	f.Errors.Name("new_name", newName, validate.MaxGameName)
+	others := make([]string, 0)
+	for _, name := range templates.UniqueGameNames(b) {
+		if name != oldName {
+			others = append(others, name)
+		}
+	}
+	f.Errors.Unique("new_name", newName, others)
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderGames(w, r, f)
 		return
 	}
-	b := h.store.GetBoard()
 	for _, t := range b.Teams {
 		for i := range t.Games {
 			if t.Games[i].GameName == oldName {
@@ -252,9 +512,12 @@ func (h *ScoreBoardHandler) PostRenameGame(w http.ResponseWriter, r *http.Reques
 			}
 		}
 	}
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
+	b.RenameTimer(oldName, newName)
+	b.RenameGameInfo(oldName, newName)
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	http.Redirect(w, r, "/games", http.StatusSeeOther)
 }
 
@@ -269,7 +532,10 @@ func (h *ScoreBoardHandler) PostDeleteGame(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "name required", http.StatusBadRequest)
 		return
 	}
-	b := h.store.GetBoard()
+	b := h.edit()
+	if !h.fresh(w, r, b, "/games") {
+		return
+	}
 	for _, t := range b.Teams {
 		filtered := make([]store.Game, 0, len(t.Games))
 		for _, g := range t.Games {
@@ -279,16 +545,68 @@ func (h *ScoreBoardHandler) PostDeleteGame(w http.ResponseWriter, r *http.Reques
 		}
 		t.Games = filtered
 	}
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
+	b.RemoveTimer(name)
+	b.RemoveGameInfo(name)
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/games", http.StatusSeeOther)
+}
+
+// PostGameSettings sets how a game is scored and how many rounds it has,
+// across all teams.
+func (h *ScoreBoardHandler) PostGameSettings(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("game_name"))
+	scoring := r.FormValue("scoring")
+	limitStr := strings.TrimSpace(r.FormValue("round_limit"))
+	b := h.edit()
+	if !h.fresh(w, r, b, "/games") {
+		return
+	}
+	if name == "" || !slices.Contains(templates.UniqueGameNames(b), name) {
+		http.Error(w, "game does not exist", http.StatusBadRequest)
+		return
+	}
+	f := templates.GamesForm{SettingsGame: name, RoundLimit: limitStr, Errors: validate.Errors{}}
+	if !slices.Contains(store.ScoringModes, scoring) {
+		f.Errors.Add("scoring", "pick a scoring mode")
+	}
+	limit := 0
+	if limitStr != "" {
+		limit, _ = f.Errors.Int("round_limit", limitStr, 0, validate.MaxRoundLimit)
+	}
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderGames(w, r, f)
+		return
+	}
+	if scoring == store.ScoringSum {
+		scoring = ""
+	}
+	for _, t := range b.Teams {
+		for i := range t.Games {
+			if t.Games[i].GameName == name {
+				t.Games[i].Scoring = scoring
+				t.Games[i].RoundLimit = limit
+			}
+		}
+	}
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	http.Redirect(w, r, "/games", http.StatusSeeOther)
 }
 
 // GetTeamScores shows a page to edit a team's scores by game/round.
 func (h *ScoreBoardHandler) GetTeamScores(w http.ResponseWriter, r *http.Request) {
 	teamParam, _ := url.PathUnescape(chi.URLParam(r, "team"))
-	b := h.store.GetBoard()
+	b := h.current()
 	var team *store.Team
 	for _, t := range b.Teams {
 		if t != nil && t.TeamName == teamParam {
@@ -300,13 +618,55 @@ func (h *ScoreBoardHandler) GetTeamScores(w http.ResponseWriter, r *http.Request
 		http.NotFound(w, r)
 		return
 	}
-	c := templates.TeamScores(team)
+	h.renderTeamScores(w, r, team, templates.ScoreForm{})
+}
+
+func (h *ScoreBoardHandler) renderTeamScores(w http.ResponseWriter, r *http.Request, team *store.Team, f templates.ScoreForm) {
+	version := h.current().Version
+	if isHTMX(r) && f.GameName != "" {
+		if g := teamGame(team, f.GameName); g != nil {
+			h.renderTeamGame(w, r, team, *g, seenVersion(r, team, g.GameName, version), f)
+			return
+		}
+	}
+	c := templates.TeamScores(team, version, f)
 	if err := templates.Layout(c, "Team Scores").Render(r.Context(), w); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// renderTeamGame answers an htmx edit with just the game's card.
+func (h *ScoreBoardHandler) renderTeamGame(w http.ResponseWriter, r *http.Request, team *store.Team, g store.Game, version int, f templates.ScoreForm) {
+	if err := templates.TeamGameUpdate(team, g, version, f).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// isHTMX reports whether a request came from htmx, which wants a fragment
+// back rather than a redirect.
+func isHTMX(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// seenVersion is the board version a team page carries on with after an
+// in-place edit to game: the new one if none of the team's other games
+// changed since the page saw the board, otherwise the one it had, so their
+// forms are still caught as stale. The game's own card comes back up to
+// date either way.
+func seenVersion(r *http.Request, team *store.Team, game string, after int) int {
+	v, err := strconv.Atoi(r.FormValue("version"))
+	if err != nil {
+		return after
+	}
+	for _, g := range team.Games {
+		if g.GameName != game && g.Changed > v {
+			return v
+		}
+	}
+	return after
+}
+
 // PostTeamScores upserts a round score for a specific team and game.
 func (h *ScoreBoardHandler) PostTeamScores(w http.ResponseWriter, r *http.Request) {
 	teamParam, _ := url.PathUnescape(chi.URLParam(r, "team"))
@@ -317,11 +677,11 @@ func (h *ScoreBoardHandler) PostTeamScores(w http.ResponseWriter, r *http.Reques
 	gameName := strings.TrimSpace(r.FormValue("game_name"))
 	roundName := strings.TrimSpace(r.FormValue("round_name"))
 	scoreStr := strings.TrimSpace(r.FormValue("score"))
-	if gameName == "" || scoreStr == "" {
-		http.Error(w, "game and score required", http.StatusBadRequest)
+	if gameName == "" {
+		http.Error(w, "game required", http.StatusBadRequest)
 		return
 	}
-	b := h.store.GetBoard()
+	b := h.edit()
 	var team *store.Team
 	for _, t := range b.Teams {
 		if t != nil && t.TeamName == teamParam {
@@ -347,20 +707,35 @@ func (h *ScoreBoardHandler) PostTeamScores(w http.ResponseWriter, r *http.Reques
 	if game.Rounds == nil {
 		game.Rounds = make(map[string]int)
 	}
+	f := templates.ScoreForm{GameName: gameName, Score: scoreStr, Errors: validate.Errors{}}
+	scoreVal, _ := f.Errors.Score("score", scoreStr)
+	// round_name has no box of its own, so report it next to the score.
+	f.Errors.OptionalName("score", roundName, validate.MaxRoundName)
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderTeamScores(w, r, team, f)
+		return
+	}
+	if _, exists := game.Rounds[roundName]; !exists && game.RoundsFull() {
+		f.Errors.Add("score", "round limit reached ("+strconv.Itoa(game.RoundLimit)+" rounds)")
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderTeamScores(w, r, team, f)
+		return
+	}
 	// Determine next round using max(existing)+1 to avoid gaps after deletions
 	if roundName == "" {
 		next := templates.NextRoundForGame(*game)
 		roundName = strconv.Itoa(next)
 	}
-	scoreVal, err := strconv.Atoi(scoreStr)
-	if err != nil {
-		http.Error(w, "score must be a number", http.StatusBadRequest)
+	game.Rounds[roundName] = scoreVal
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if isHTMX(r) {
+		h.renderTeamGame(w, r, team, *game, seenVersion(r, team, game.GameName, b.Version), templates.ScoreForm{})
 		return
 	}
-	game.Rounds[roundName] = scoreVal
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
 	http.Redirect(w, r, "/board/team/"+url.PathEscape(team.TeamName), http.StatusSeeOther)
 }
 
@@ -376,7 +751,7 @@ func (h *ScoreBoardHandler) PostTeamScoresBulk(w http.ResponseWriter, r *http.Re
 		http.Error(w, "game required", http.StatusBadRequest)
 		return
 	}
-	b := h.store.GetBoard()
+	b := h.edit()
 	var team *store.Team
 	for _, t := range b.Teams {
 		if t != nil && t.TeamName == teamParam {
@@ -399,27 +774,58 @@ func (h *ScoreBoardHandler) PostTeamScoresBulk(w http.ResponseWriter, r *http.Re
 		http.Error(w, "game does not exist; add it in Games", http.StatusBadRequest)
 		return
 	}
+	if !h.freshGame(w, r, b, game, "/board/team/"+url.PathEscape(teamParam)) {
+		return
+	}
 	if game.Rounds == nil {
 		game.Rounds = make(map[string]int)
 	}
-	// Expect multiple round_name and score fields (parallel slices by order)
+	// Expect multiple round_name and score fields (parallel slices by order).
+	// Validate everything first so a bad value doesn't leave a half-applied edit.
 	roundNames := r.Form["round_name"]
 	scores := r.Form["score"]
+	f := templates.ScoreForm{GameName: gameName, Errors: validate.Errors{}}
+	updates := make(map[string]int)
 	for i := 0; i < len(roundNames) && i < len(scores); i++ {
 		rn := strings.TrimSpace(roundNames[i])
 		sc := strings.TrimSpace(scores[i])
 		if rn == "" || sc == "" {
 			continue
 		}
-		val, err := strconv.Atoi(sc)
-		if err != nil {
+		errs := validate.Errors{}
+		if val, ok := errs.Score("score", sc); ok {
+			updates[rn] = val
 			continue
 		}
+		f.Errors.Add("bulk", "Round "+rn+": "+errs.Get("score"))
+	}
+	if game.RoundLimit > 0 {
+		count := len(game.Rounds)
+		for rn := range updates {
+			if _, exists := game.Rounds[rn]; !exists {
+				count++
+			}
+		}
+		if count > game.RoundLimit {
+			f.Errors.Add("bulk", "round limit reached ("+strconv.Itoa(game.RoundLimit)+" rounds)")
+		}
+	}
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderTeamScores(w, r, team, f)
+		return
+	}
+	for rn, val := range updates {
 		game.Rounds[rn] = val
 	}
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if isHTMX(r) {
+		h.renderTeamGame(w, r, team, *game, seenVersion(r, team, game.GameName, b.Version), templates.ScoreForm{})
+		return
+	}
 	http.Redirect(w, r, "/board/team/"+url.PathEscape(team.TeamName), http.StatusSeeOther)
 }
 
@@ -436,7 +842,7 @@ func (h *ScoreBoardHandler) PostDeleteRound(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "game and round required", http.StatusBadRequest)
 		return
 	}
-	b := h.store.GetBoard()
+	b := h.edit()
 	var team *store.Team
 	for _, t := range b.Teams {
 		if t != nil && t.TeamName == teamParam {
@@ -448,16 +854,24 @@ func (h *ScoreBoardHandler) PostDeleteRound(w http.ResponseWriter, r *http.Reque
 		http.NotFound(w, r)
 		return
 	}
-	for i := range team.Games {
-		if team.Games[i].GameName == gameName {
-			if team.Games[i].Rounds != nil {
-				delete(team.Games[i].Rounds, roundName)
-			}
-			break
-		}
+	game := teamGame(team, gameName)
+	if game == nil {
+		http.Error(w, "game does not exist", http.StatusBadRequest)
+		return
+	}
+	if !h.freshGame(w, r, b, game, "/board/team/"+url.PathEscape(teamParam)) {
+		return
+	}
+	if game.Rounds != nil {
+		delete(game.Rounds, roundName)
+	}
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if isHTMX(r) {
+		h.renderTeamGame(w, r, team, *game, seenVersion(r, team, game.GameName, b.Version), templates.ScoreForm{})
+		return
 	}
-	_ = os.MkdirAll("./data", 0755)
-	_ = b.SaveToJSON(filepath.Join("./data", "db.json"))
-	h.store = store.LoadBoard(filepath.Join("./data", "db.json"))
 	http.Redirect(w, r, "/board/team/"+url.PathEscape(team.TeamName), http.StatusSeeOther)
 }
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/csrf"
+	"github.com/mrjxtr-dev/score-board/internal/live"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+// newTestHandlers sets up handlers over a board with teams Red and Blue and
+// one game, Darts, kept in a temporary data directory.
+func newTestHandlers(t *testing.T) (*Handlers, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.DataDir = dir
+
+	b := store.NewBoard("Test")
+	for _, name := range []string{"Red", "Blue"} {
+		b.AddTeam(&store.Team{
+			TeamName: name,
+			Games:    []store.Game{{GameName: "Darts", Rounds: map[string]int{}}},
+		})
+	}
+	if err := b.SaveToJSON(filepath.Join(dir, store.DBFilename)); err != nil {
+		t.Fatal(err)
+	}
+
+	am := auth.NewManager(auth.LoadUsers(dir), auth.LoadTeamTokens(dir))
+	return NewHandlers(cfg, store.LoadDB(dir), am, live.NewBroker()), dir
+}
+
+// scoreRouter routes team score posts the way the app does.
+func scoreRouter(h *Handlers) chi.Router {
+	r := chi.NewRouter()
+	r.With(h.Board.Writes).Post("/board/team/{team}/scores", h.Board.PostTeamScores)
+	return r
+}
+
+// noRedirects hands back the handler's own response.
+var noRedirects = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+// savedRounds reads a team's Darts rounds back from db.json.
+func savedRounds(t *testing.T, dir, team string) map[string]int {
+	t.Helper()
+	b := store.LoadBoard(filepath.Join(dir, store.DBFilename)).GetBoard()
+	for _, tm := range b.Teams {
+		if tm != nil && tm.TeamName == team {
+			if g := teamGame(tm, "Darts"); g != nil {
+				return g.Rounds
+			}
+		}
+	}
+	t.Fatalf("%s has no Darts game in db.json", team)
+	return nil
+}
+
+func TestShutdownKeepsSlowWrite(t *testing.T) {
+	h, dir := newTestHandlers(t)
+
+	var once sync.Once
+	started := make(chan struct{})
+	routes := scoreRouter(h)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(started) })
+		routes.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	// Send the form in two halves, shutting down in between
+	form := url.Values{"game_name": {"Darts"}, "round_name": {"1"}, "score": {"7"}}.Encode()
+	body, pw := io.Pipe()
+	posted := make(chan error, 1)
+	go func() {
+		res, err := noRedirects.Post(srv.URL+"/board/team/Red/scores", "application/x-www-form-urlencoded", body)
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode != http.StatusSeeOther {
+				err = fmt.Errorf("unexpected status %s", res.Status)
+			}
+		}
+		posted <- err
+	}()
+	if _, err := io.WriteString(pw, form[:len(form)/2]); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- srv.Config.Shutdown(ctx)
+	}()
+	// Shutdown has to wait for the request still being sent
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case err := <-stopped:
+		t.Fatalf("shutdown returned before the request finished: %v", err)
+	default:
+	}
+	if _, err := io.WriteString(pw, form[len(form)/2:]); err != nil {
+		t.Fatal(err)
+	}
+	pw.Close()
+
+	if err := <-posted; err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := savedRounds(t, dir, "Red")["1"]; got != 7 {
+		t.Fatalf("db.json has Red round 1 = %d, want 7", got)
+	}
+}
+
+func TestConcurrentWritesAllSaved(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	srv := httptest.NewServer(scoreRouter(h))
+	defer srv.Close()
+
+	const n = 40
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			form := url.Values{"game_name": {"Darts"}, "score": {"1"}}
+			res, err := noRedirects.Post(srv.URL+"/board/team/Blue/scores", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+			if err != nil {
+				t.Errorf("post %d: %v", i, err)
+				return
+			}
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if err := h.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := len(savedRounds(t, dir, "Blue")); got != n {
+		t.Fatalf("db.json has %d rounds for Blue, want %d", got, n)
+	}
+}
+
+func TestCSRFRejectedPostLeavesBoard(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	r := chi.NewRouter()
+	r.Use(csrf.Middleware)
+	r.With(h.Board.Writes).Post("/board/team/{team}/scores", h.Board.PostTeamScores)
+
+	send := func(token string) int {
+		form := url.Values{"game_name": {"Darts"}, "round_name": {"1"}, "score": {"9"}}
+		if token != "" {
+			form.Set(csrf.FieldName, token)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/board/team/Red/scores", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: csrf.CookieName, Value: "token"})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, token := range []string{"", "forged"} {
+		if code := send(token); code != http.StatusForbidden {
+			t.Fatalf("token %q: got %d, want 403", token, code)
+		}
+		if rounds := savedRounds(t, dir, "Red"); len(rounds) != 0 {
+			t.Fatalf("token %q: db.json has rounds %v, want none", token, rounds)
+		}
+		if v := h.Board.current().Version; v != 0 {
+			t.Fatalf("token %q: board version %d, want 0", token, v)
+		}
+	}
+
+	if code := send("token"); code != http.StatusSeeOther {
+		t.Fatalf("valid token: got %d, want 303", code)
+	}
+	if got := savedRounds(t, dir, "Red")["1"]; got != 9 {
+		t.Fatalf("valid token: db.json has Red round 1 = %d, want 9", got)
+	}
+}
+
+func TestTeamEditsDontClashAcrossTeams(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(h.Board.Writes)
+		r.Post("/board/team/{team}/scores", h.Board.PostTeamScores)
+		r.Post("/board/team/{team}/scores/bulk", h.Board.PostTeamScoresBulk)
+		r.Post("/board/team/{team}/scores/delete", h.Board.PostDeleteRound)
+	})
+	boardVersion := regexp.MustCompile(`id="board-version" value="(\d+)"`)
+
+	// send posts the way the team page does with htmx and returns the
+	// version the page carries on with
+	send := func(team, path string, form url.Values, version string) (int, string) {
+		t.Helper()
+		if version != "" {
+			form.Set("version", version)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/board/team/"+team+path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		m := boardVersion.FindStringSubmatch(w.Body.String())
+		if m == nil {
+			return w.Code, version
+		}
+		return w.Code, m[1]
+	}
+	score := func(team, round, score string) url.Values {
+		return url.Values{"game_name": {"Darts"}, "round_name": {round}, "score": {score}}
+	}
+
+	// Both team pages are opened, then scored in turns
+	red, blue := "0", "0"
+	var code int
+	for i, step := range []func(){
+		func() { code, red = send("Red", "/scores", score("Red", "1", "4"), red) },
+		func() { code, blue = send("Blue", "/scores", score("Blue", "1", "6"), blue) },
+		func() { code, red = send("Red", "/scores/bulk", score("Red", "1", "5"), red) },
+		func() { code, blue = send("Blue", "/scores/bulk", score("Blue", "1", "7"), blue) },
+		func() { code, red = send("Red", "/scores", score("Red", "2", "3"), red) },
+		func() { code, blue = send("Blue", "/scores/delete", score("Blue", "1", ""), blue) },
+		func() { code, red = send("Red", "/scores/delete", score("Red", "2", ""), red) },
+	} {
+		step()
+		if code != http.StatusOK {
+			t.Fatalf("step %d: got %d, want 200", i, code)
+		}
+	}
+	if rounds := savedRounds(t, dir, "Red"); len(rounds) != 1 || rounds["1"] != 5 {
+		t.Fatalf("db.json has Red rounds %v, want just 1: 5", rounds)
+	}
+	if rounds := savedRounds(t, dir, "Blue"); len(rounds) != 0 {
+		t.Fatalf("db.json has Blue rounds %v, want none", rounds)
+	}
+
+	// A change to Red's own game still makes its page stale
+	if code, _ := send("Red", "/scores", score("Red", "1", "9"), ""); code != http.StatusOK {
+		t.Fatalf("second scorekeeper: got %d, want 200", code)
+	}
+	if code, _ := send("Red", "/scores/bulk", score("Red", "1", "2"), red); code != http.StatusConflict {
+		t.Fatalf("stale bulk edit: got %d, want 409", code)
+	}
+	if got := savedRounds(t, dir, "Red")["1"]; got != 9 {
+		t.Fatalf("db.json has Red round 1 = %d, want 9", got)
+	}
+}
+
+func TestTeamPageEscapesFormActions(t *testing.T) {
+	h, _ := newTestHandlers(t)
+	b := h.Board.edit()
+	b.Teams[0].TeamName = "A#1"
+	b.Teams[0].Games[0].Rounds["1"] = 4 // so the delete form shows up
+	if err := h.Board.persist(b); err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	r.Get("/board/team/{team}", h.Board.GetTeamScores)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/board/team/A%231", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d", w.Code)
+	}
+	// Without JavaScript the forms post to action, where a bare # would end
+	// the path at "/board/team/A"
+	for _, path := range []string{"/scores", "/scores/delete", "/scores/bulk"} {
+		want := `action="/board/team/A%231` + path + `"`
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("page has no form with %s", want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/chart"
+	"github.com/mrjxtr-dev/score-board/internal/stats"
+)
+
+// progressionChart turns the running totals into a line chart. With a team
+// name only that team's line is drawn.
+func progressionChart(title string, p stats.Progression, team string) chart.LineChart {
+	c := chart.LineChart{Title: title, Labels: p.Labels}
+	for _, g := range p.Games {
+		c.Groups = append(c.Groups, chart.Group{Name: g.Game, Start: g.Start, End: g.End})
+	}
+	for _, t := range p.Teams {
+		if team != "" && t.Team != team {
+			continue
+		}
+		values := make([]float64, len(t.Totals))
+		for i, v := range t.Totals {
+			values[i] = float64(v)
+		}
+		c.Series = append(c.Series, chart.Series{Name: t.Team, Color: t.Color, Values: values})
+	}
+	return c
+}
+
+// writeChart sends a chart as SVG; ?download=1 saves it as a file instead.
+func writeChart(w http.ResponseWriter, r *http.Request, c chart.LineChart, filename string) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-store")
+	if r.URL.Query().Get("download") != "" {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.svg"`)
+	}
+	_ = c.WriteSVG(w)
+}
+
+// GetBoardChart renders every team's score progression as SVG.
+func (h *ScoreBoardHandler) GetBoardChart(w http.ResponseWriter, r *http.Request) {
+	b := h.current()
+	c := progressionChart(b.BoardName+" — score progression", stats.ComputeProgression(b), "")
+	writeChart(w, r, c, fileSlug(b.BoardName))
+}
+
+// GetTeamChart renders one team's score progression as SVG.
+func (h *ScoreBoardHandler) GetTeamChart(w http.ResponseWriter, r *http.Request) {
+	teamParam, _ := url.PathUnescape(chi.URLParam(r, "team"))
+	b := h.current()
+	found := false
+	for _, t := range b.Teams {
+		if t != nil && t.TeamName == teamParam {
+			found = true
+			break
+		}
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	c := progressionChart(teamParam+" — score progression", stats.ComputeProgression(b), teamParam)
+	writeChart(w, r, c, fileSlug(b.BoardName+" "+teamParam))
+}
+
+// fileSlug turns a name into something safe to use as a download filename.
+func fileSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "scoreboard"
+	}
+	return slug
+}
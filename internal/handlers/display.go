@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+)
+
+const (
+	defaultDisplayInterval = 15
+	minDisplayInterval     = 5
+	maxDisplayInterval     = 300
+)
+
+// parseDisplayOptions reads the kiosk settings from the query string.
+// Unknown panels are ignored; anything invalid falls back to the defaults.
+func parseDisplayOptions(r *http.Request) templates.DisplayOptions {
+	q := r.URL.Query()
+	o := templates.DisplayOptions{
+		Interval: defaultDisplayInterval,
+		Ticker:   q.Get("ticker") == "1" || q.Get("ticker") == "true",
+		Title:    q.Get("title") != "0" && q.Get("title") != "false",
+		Query:    r.URL.RawQuery,
+	}
+	for _, p := range strings.Split(q.Get("views"), ",") {
+		p = strings.TrimSpace(p)
+		if (p == templates.PanelStandings || p == templates.PanelGames) && !o.Shows(p) {
+			o.Panels = append(o.Panels, p)
+		}
+	}
+	if len(o.Panels) == 0 {
+		o.Panels = []string{templates.PanelStandings}
+	}
+	if v, err := strconv.Atoi(q.Get("interval")); err == nil {
+		o.Interval = min(max(v, minDisplayInterval), maxDisplayInterval)
+	}
+	return o
+}
+
+// GetDisplay renders the full-screen, read-only kiosk view.
+func (h *ScoreBoardHandler) GetDisplay(w http.ResponseWriter, r *http.Request) {
+	c := templates.DisplayPage(h.current(), parseDisplayOptions(r))
+	if err := c.Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDisplayPanels renders just the panels so the display can refresh in place.
+func (h *ScoreBoardHandler) GetDisplayPanels(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	c := templates.DisplayPanels(h.current(), parseDisplayOptions(r))
+	if err := c.Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
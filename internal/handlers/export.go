@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/export"
+)
+
+// writeResults renders the results sheet in the format named by {format}
+// ("pdf" or "png"). It renders into a buffer first so a failure still
+// gets a proper error response.
+func writeResults(w http.ResponseWriter, r *http.Request, res export.Results) {
+	var buf bytes.Buffer
+	var err error
+	contentType := ""
+	switch chi.URLParam(r, "format") {
+	case "pdf":
+		contentType = "application/pdf"
+		err = export.WritePDF(&buf, res)
+	case "png":
+		contentType = "image/png"
+		err = export.WritePNG(&buf, res)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	if r.URL.Query().Get("download") != "" {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+fileSlug(res.BoardName)+`-results.`+chi.URLParam(r, "format")+`"`)
+	}
+	_, _ = buf.WriteTo(w)
+}
+
+// GetResults exports the current board's standings and per-game scores.
+func (h *ScoreBoardHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	writeResults(w, r, export.FromBoard(h.current(), time.Now()))
+}
+
+// GetArchivedResults exports the final results of a past event.
+func (h *ScoreBoardHandler) GetArchivedResults(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.archive.Get(chi.URLParam(r, "id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeResults(w, r, export.FromArchive(entry))
+}
@@ -1,15 +1,32 @@
 package handlers
 
-import "github.com/mrjxtr-dev/score-board/internal/store"
+import (
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/live"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
 
 type Handlers struct {
-	Board *ScoreBoardHandler
-	Home  *HomeHandler
+	Auth   *AuthHandler
+	Board  *ScoreBoardHandler
+	Home   *HomeHandler
+	Season *SeasonHandler
 }
 
-func NewHandlers(db store.Database) *Handlers {
+func NewHandlers(cfg *config.Config, db store.Database, am *auth.Manager, lv *live.Broker) *Handlers {
+	archive := store.LoadArchive(cfg.DataDir)
+	syncLog := store.LoadSyncLog(cfg.DataDir)
+	syncLog.TTL = cfg.Retention.SyncLog
 	return &Handlers{
-		Board: NewScoreBoardHandler(db),
-		Home:  NewHomeHandler(db),
+		Auth:   NewAuthHandler(am),
+		Board:  NewScoreBoardHandler(db, cfg, am.Tokens, lv, store.LoadTemplates(cfg.DataDir), archive, syncLog),
+		Home:   NewHomeHandler(db),
+		Season: NewSeasonHandler(store.LoadSeasons(cfg.DataDir), archive),
 	}
 }
+
+// Flush saves anything still only in memory, for a clean shutdown.
+func (h *Handlers) Flush() error {
+	return h.Board.Flush()
+}
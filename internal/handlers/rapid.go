@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+const maxRapidEntries = 200
+
+// rapidEntry is one score typed on the rapid entry screen. An empty round
+// goes to the team's next round. Undo takes a round back out, as long as it
+// still holds the same score.
+type rapidEntry struct {
+	Team  string `json:"team"`
+	Game  string `json:"game"`
+	Round string `json:"round,omitempty"`
+	Score int    `json:"score"`
+	Undo  bool   `json:"undo,omitempty"`
+}
+
+type rapidRequest struct {
+	Entries []rapidEntry `json:"entries"`
+}
+
+type rapidError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+type rapidResponse struct {
+	Results []rapidEntry `json:"results,omitempty"`
+	Errors  []rapidError `json:"errors,omitempty"`
+	Version int          `json:"version"`
+}
+
+// GetRapidEntry shows the keyboard-driven entry screen. ?game= picks the
+// game to start with.
+func (h *ScoreBoardHandler) GetRapidEntry(w http.ResponseWriter, r *http.Request) {
+	b := h.current()
+	games := templates.UniqueGameNames(b)
+	game := r.URL.Query().Get("game")
+	if !slices.Contains(games, game) && len(games) > 0 {
+		game = games[0]
+	}
+	c := templates.RapidEntry(b, game)
+	if err := templates.Layout(c, "Rapid Entry").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostRapidBatch applies a sequence of entries all at once: every entry is
+// checked against the board as it would be after the ones before it, and if
+// any of them fails nothing is saved and the errors come back by index.
+func (h *ScoreBoardHandler) PostRapidBatch(w http.ResponseWriter, r *http.Request) {
+	var req rapidRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Entries) == 0 {
+		http.Error(w, "no entries", http.StatusBadRequest)
+		return
+	}
+	if len(req.Entries) > maxRapidEntries {
+		http.Error(w, "too many entries; send at most "+strconv.Itoa(maxRapidEntries), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	b := h.edit()
+	// Work on copies of the rounds so a bad entry leaves the board alone
+	work := make(map[*store.Game]map[string]int)
+	rounds := func(g *store.Game) map[string]int {
+		if m, ok := work[g]; ok {
+			return m
+		}
+		m := maps.Clone(g.Rounds)
+		if m == nil {
+			m = make(map[string]int)
+		}
+		work[g] = m
+		return m
+	}
+
+	resp := rapidResponse{Results: make([]rapidEntry, 0, len(req.Entries))}
+	for i, e := range req.Entries {
+		e.Team = strings.TrimSpace(e.Team)
+		e.Game = strings.TrimSpace(e.Game)
+		e.Round = strings.TrimSpace(e.Round)
+		fail := func(msg string) {
+			resp.Errors = append(resp.Errors, rapidError{Index: i, Error: msg})
+		}
+
+		errs := validate.Errors{}
+		errs.OptionalName("round", e.Round, validate.MaxRoundName)
+		errs.Score("score", strconv.Itoa(e.Score))
+		if errs.Any() {
+			if msg := errs.Get("round"); msg != "" {
+				fail("round " + msg)
+			} else {
+				fail("score " + errs.Get("score"))
+			}
+			continue
+		}
+		var team *store.Team
+		for _, t := range b.Teams {
+			if t != nil && t.TeamName == e.Team {
+				team = t
+				break
+			}
+		}
+		if team == nil {
+			fail("team does not exist")
+			continue
+		}
+		g := teamGame(team, e.Game)
+		if g == nil {
+			fail("game does not exist")
+			continue
+		}
+		m := rounds(g)
+
+		if e.Undo {
+			if sc, ok := m[e.Round]; !ok || e.Round == "" {
+				fail("round " + e.Round + " is already gone")
+			} else if sc != e.Score {
+				fail("round " + e.Round + " was changed to " + strconv.Itoa(sc) + " since")
+			} else {
+				delete(m, e.Round)
+				resp.Results = append(resp.Results, e)
+			}
+			continue
+		}
+		scratch := store.Game{Rounds: m, RoundLimit: g.RoundLimit}
+		if _, exists := m[e.Round]; (!exists || e.Round == "") && scratch.RoundsFull() {
+			fail("round limit reached (" + strconv.Itoa(g.RoundLimit) + " rounds)")
+			continue
+		}
+		if e.Round == "" {
+			e.Round = strconv.Itoa(templates.NextRoundForGame(scratch))
+		}
+		m[e.Round] = e.Score
+		resp.Results = append(resp.Results, e)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if len(resp.Errors) > 0 {
+		resp.Results = nil
+		resp.Version = b.Version
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+	for g, m := range work {
+		g.Rounds = m
+	}
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Version = b.Version
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/schedule"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+// PostScheduleGame sets a game's time slot and location.
+// Leaving both times empty unschedules the game.
+func (h *ScoreBoardHandler) PostScheduleGame(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	gameName := strings.TrimSpace(r.FormValue("game_name"))
+	b := h.edit()
+	if !h.fresh(w, r, b, "/games") {
+		return
+	}
+	exists := false
+	for _, g := range templates.UniqueGameNames(b) {
+		if g == gameName {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		http.Error(w, "game does not exist; add it in Games", http.StatusBadRequest)
+		return
+	}
+
+	f := templates.GamesForm{
+		ScheduleGame: gameName,
+		Start:        r.FormValue("start"),
+		End:          r.FormValue("end"),
+		Location:     strings.TrimSpace(r.FormValue("location")),
+		Errors:       validate.Errors{},
+	}
+	start := f.Errors.DateTime("start", f.Start, templates.DateTimeLocal)
+	end := f.Errors.DateTime("end", f.End, templates.DateTimeLocal)
+	f.Errors.OptionalText("location", f.Location, validate.MaxLocation)
+	switch {
+	case start.IsZero() != end.IsZero():
+		if start.IsZero() {
+			f.Errors.Add("start", "required when an end time is set")
+		} else {
+			f.Errors.Add("end", "required when a start time is set")
+		}
+	case !start.IsZero() && !end.After(start):
+		f.Errors.Add("end", "must be after the start time")
+	}
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderGames(w, r, f)
+		return
+	}
+
+	info := b.EnsureGameInfo(gameName)
+	info.Start = start
+	info.End = end
+	info.Location = f.Location
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/games", http.StatusSeeOther)
+}
+
+// GetSchedule shows the event timeline.
+func (h *ScoreBoardHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	b := h.current()
+	c := templates.Schedule(b, schedule.Timeline(b, time.Now()))
+	if err := templates.Layout(c, "Schedule").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetNowPlaying renders the "now playing / up next" banner so the board can
+// refresh it as the clock moves on.
+func (h *ScoreBoardHandler) GetNowPlaying(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if err := templates.NowPlaying(h.current(), time.Now()).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetScheduleICS exports the schedule as an iCalendar file.
+func (h *ScoreBoardHandler) GetScheduleICS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="schedule.ics"`)
+	if err := schedule.WriteICS(w, h.current(), time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/export"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+const (
+	defaultSheetRounds = 5
+	maxSheetRounds     = 12
+)
+
+// sheetGame reads the {game} URL param and checks the game exists.
+func (h *ScoreBoardHandler) sheetGame(w http.ResponseWriter, r *http.Request) (string, bool) {
+	game, _ := url.PathUnescape(chi.URLParam(r, "game"))
+	if !slices.Contains(templates.UniqueGameNames(h.current()), game) {
+		http.NotFound(w, r)
+		return "", false
+	}
+	return game, true
+}
+
+// sheetRounds works out which rounds go on a sheet: by default it picks up
+// after the highest round any team already has, and covers the rest of the
+// round limit (or a handful of rounds when there's no limit). ?first= and
+// ?rounds= override both.
+func sheetRounds(r *http.Request, b *store.ScoreBoard, game string) []string {
+	first := 1
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			if g.GameName == game {
+				first = max(first, templates.NextRoundForGame(g))
+			}
+		}
+	}
+	settings := templates.GameSettings(b, game)
+	count := defaultSheetRounds
+	if settings.RoundLimit > 0 {
+		count = max(settings.RoundLimit-first+1, 1)
+	}
+	if v, err := strconv.Atoi(r.FormValue("first")); err == nil && v >= 1 {
+		first = v
+	}
+	if v, err := strconv.Atoi(r.FormValue("rounds")); err == nil {
+		count = v
+	}
+	count = min(max(count, 1), maxSheetRounds)
+
+	rounds := make([]string, count)
+	for i := range rounds {
+		rounds[i] = strconv.Itoa(first + i)
+	}
+	return rounds
+}
+
+func (h *ScoreBoardHandler) scoresheet(r *http.Request, game string) export.Scoresheet {
+	b := h.current()
+	s := export.Scoresheet{
+		BoardName: b.BoardName,
+		Game:      game,
+		Scoring:   templates.ScoringLabel(templates.GameSettings(b, game)),
+		Date:      time.Now(),
+		Rounds:    sheetRounds(r, b, game),
+	}
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		s.Teams = append(s.Teams, export.SheetTeam{Name: t.TeamName, Color: export.ParseHex(t.TeamColor["color"])})
+	}
+	return s
+}
+
+// GetScoresheets lists the games with links to print, download and
+// transcribe their scoresheets.
+func (h *ScoreBoardHandler) GetScoresheets(w http.ResponseWriter, r *http.Request) {
+	b := h.current()
+	c := templates.Scoresheets(b)
+	if err := templates.Layout(c, "Scoresheets").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetScoresheetPrint renders a game's scoresheet as a page made for printing.
+func (h *ScoreBoardHandler) GetScoresheetPrint(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.sheetGame(w, r)
+	if !ok {
+		return
+	}
+	if err := templates.ScoresheetPrint(h.scoresheet(r, game)).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetScoresheetPDF sends a game's scoresheet as a PDF.
+func (h *ScoreBoardHandler) GetScoresheetPDF(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.sheetGame(w, r)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := export.WriteScoresheetPDF(&buf, h.scoresheet(r, game)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileSlug(game)+`-scoresheet.pdf"`)
+	_, _ = buf.WriteTo(w)
+}
+
+// GetScoresheetEntry shows a grid matching the paper sheet so a filled-in
+// sheet can be typed back in.
+func (h *ScoreBoardHandler) GetScoresheetEntry(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.sheetGame(w, r)
+	if !ok {
+		return
+	}
+	h.renderScoresheetEntry(w, r, game, sheetRounds(r, h.current(), game), templates.SheetForm{})
+}
+
+func (h *ScoreBoardHandler) renderScoresheetEntry(w http.ResponseWriter, r *http.Request, game string, rounds []string, f templates.SheetForm) {
+	c := templates.ScoresheetEntry(h.current(), game, rounds, f)
+	if err := templates.Layout(c, "Enter Scoresheet").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostScoresheetEntry saves a whole transcribed sheet at once. Blank boxes
+// are skipped; if any box is invalid nothing is saved.
+func (h *ScoreBoardHandler) PostScoresheetEntry(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.sheetGame(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	b := h.edit()
+	if !h.fresh(w, r, b, "/scoresheets/"+url.PathEscape(game)+"/entry") {
+		return
+	}
+
+	var rounds []string
+	for _, rn := range r.Form["round"] {
+		rn = strings.TrimSpace(rn)
+		if rn != "" && !slices.Contains(rounds, rn) {
+			rounds = append(rounds, rn)
+		}
+	}
+	// Rows are posted by their index in b.Teams (empty slots keep theirs);
+	// make sure that still lines up.
+	for i, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		if r.FormValue("team_"+strconv.Itoa(i)) != t.TeamName {
+			http.Error(w, "the teams changed since this page was loaded; reload and try again", http.StatusConflict)
+			return
+		}
+	}
+
+	f := templates.SheetForm{Values: make(map[string]string), Errors: validate.Errors{}}
+	updates := make(map[int]map[string]int)
+	for i, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		updates[i] = make(map[string]int)
+		for _, rn := range rounds {
+			field := templates.SheetField(i, rn)
+			raw := strings.TrimSpace(r.FormValue(field))
+			f.Values[field] = raw
+			f.Errors.OptionalName(field, rn, validate.MaxRoundName)
+			if raw == "" {
+				continue
+			}
+			if v, ok := f.Errors.Score(field, raw); ok {
+				updates[i][rn] = v
+			}
+		}
+		g := teamGame(t, game)
+		if g == nil || g.RoundLimit == 0 {
+			continue
+		}
+		count := len(g.Rounds)
+		for rn := range updates[i] {
+			if _, exists := g.Rounds[rn]; !exists {
+				count++
+			}
+		}
+		if count > g.RoundLimit {
+			f.Errors.Add("sheet", t.TeamName+": round limit reached ("+strconv.Itoa(g.RoundLimit)+" rounds)")
+		}
+	}
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderScoresheetEntry(w, r, game, rounds, f)
+		return
+	}
+
+	for i, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		g := teamGame(t, game)
+		if g == nil || len(updates[i]) == 0 {
+			continue
+		}
+		if g.Rounds == nil {
+			g.Rounds = make(map[string]int)
+		}
+		for rn, v := range updates[i] {
+			g.Rounds[rn] = v
+		}
+	}
+	if err := h.save(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/board", http.StatusSeeOther)
+}
+
+// teamGame returns the team's copy of a game, or nil.
+func teamGame(t *store.Team, game string) *store.Game {
+	for i := range t.Games {
+		if t.Games[i].GameName == game {
+			return &t.Games[i]
+		}
+	}
+	return nil
+}
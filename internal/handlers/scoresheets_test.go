@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+)
+
+func TestScoresheetSkipsEmptyTeamSlot(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	b := h.Board.edit()
+	b.Teams = slices.Insert(b.Teams, 1, nil) // Red, empty, Blue
+	if err := h.Board.persist(b); err != nil {
+		t.Fatal(err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/scoresheets/{game}/print", h.Board.GetScoresheetPrint)
+	r.Get("/scoresheets/{game}/sheet.pdf", h.Board.GetScoresheetPDF)
+	r.Get("/scoresheets/{game}/entry", h.Board.GetScoresheetEntry)
+	r.With(h.Board.Writes).Post("/scoresheets/{game}/entry", h.Board.PostScoresheetEntry)
+
+	for _, path := range []string{"print", "sheet.pdf", "entry"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scoresheets/Darts/"+path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got %d: %s", path, w.Code, w.Body)
+		}
+		if path == "entry" && !strings.Contains(w.Body.String(), `name="team_2" value="Blue"`) {
+			t.Fatalf("entry page doesn't post Blue under its own slot")
+		}
+	}
+
+	form := url.Values{
+		"round":                      {"1"},
+		"team_0":                     {"Red"},
+		"team_2":                     {"Blue"},
+		templates.SheetField(0, "1"): {"3"},
+		templates.SheetField(2, "1"): {"4"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/scoresheets/Darts/entry", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("entry: got %d: %s", w.Code, w.Body)
+	}
+	if red, blue := savedRounds(t, dir, "Red")["1"], savedRounds(t, dir, "Blue")["1"]; red != 3 || blue != 4 {
+		t.Fatalf("db.json has round 1 Red = %d, Blue = %d; want 3 and 4", red, blue)
+	}
+}
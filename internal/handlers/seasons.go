@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+type SeasonHandler struct {
+	seasons *store.Seasons
+	archive *store.Archive
+}
+
+// NewSeasonHandler creates a SeasonHandler bound to the season store and
+// the archive of finished boards.
+func NewSeasonHandler(seasons *store.Seasons, archive *store.Archive) *SeasonHandler {
+	return &SeasonHandler{
+		seasons: seasons,
+		archive: archive,
+	}
+}
+
+// boards returns the season's archived boards in the order they finished.
+func (h *SeasonHandler) boards(s store.Season) []store.ArchivedBoard {
+	var list []store.ArchivedBoard
+	for _, id := range s.Boards {
+		if b, ok := h.archive.Get(id); ok {
+			list = append(list, b)
+		}
+	}
+	slices.SortStableFunc(list, func(a, b store.ArchivedBoard) int {
+		return a.FinishedAt.Compare(b.FinishedAt)
+	})
+	return list
+}
+
+// parseSeasonForm reads and validates the season fields shared by the
+// create and edit forms.
+func (h *SeasonHandler) parseSeasonForm(r *http.Request) (templates.SeasonForm, store.Season) {
+	f := templates.SeasonForm{
+		Name:    strings.TrimSpace(r.FormValue("name")),
+		Points:  r.FormValue("points"),
+		Aliases: r.FormValue("aliases"),
+		Errors:  validate.Errors{},
+	}
+	f.Errors.Name("name", f.Name, validate.MaxBoardName)
+	points := f.Errors.Points("points", f.Points)
+	aliases := f.Errors.Aliases("aliases", f.Aliases)
+	for _, id := range r.Form["boards"] {
+		if _, ok := h.archive.Get(id); ok && !slices.Contains(f.Boards, id) {
+			f.Boards = append(f.Boards, id)
+		}
+	}
+	s := store.Season{Name: f.Name, Points: points, Boards: f.Boards}
+	if len(aliases) > 0 {
+		s.Aliases = aliases
+	}
+	return f, s
+}
+
+// GetSeasons lists seasons and shows the form to start one.
+func (h *SeasonHandler) GetSeasons(w http.ResponseWriter, r *http.Request) {
+	h.renderSeasons(w, r, templates.SeasonFormFrom(store.Season{Points: store.DefaultPoints}))
+}
+
+func (h *SeasonHandler) renderSeasons(w http.ResponseWriter, r *http.Request, f templates.SeasonForm) {
+	c := templates.Seasons(h.seasons.List(), f)
+	if err := templates.Layout(c, "Seasons").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostSeason creates a season.
+func (h *SeasonHandler) PostSeason(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	f, s := h.parseSeasonForm(r)
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderSeasons(w, r, f)
+		return
+	}
+	s, err := h.seasons.Add(s)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/seasons/"+s.ID, http.StatusSeeOther)
+}
+
+// GetSeason shows the season leaderboard and, for admins, its settings.
+func (h *SeasonHandler) GetSeason(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.seasons.Get(chi.URLParam(r, "id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	h.renderSeason(w, r, s, templates.SeasonFormFrom(s))
+}
+
+func (h *SeasonHandler) renderSeason(w http.ResponseWriter, r *http.Request, s store.Season, f templates.SeasonForm) {
+	boards := h.boards(s)
+	c := templates.SeasonPage(s, boards, s.Standings(boards), h.archive.List(), f)
+	if err := templates.Layout(c, s.Name).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostUpdateSeason saves a season's name, points, boards and team aliases.
+func (h *SeasonHandler) PostUpdateSeason(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.seasons.Get(chi.URLParam(r, "id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	f, updated := h.parseSeasonForm(r)
+	if f.Errors.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderSeason(w, r, s, f)
+		return
+	}
+	updated.ID = s.ID
+	if err := h.seasons.Update(updated); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/seasons/"+s.ID, http.StatusSeeOther)
+}
+
+// PostDeleteSeason removes a season; its boards stay in the archive.
+func (h *SeasonHandler) PostDeleteSeason(w http.ResponseWriter, r *http.Request) {
+	if err := h.seasons.Remove(chi.URLParam(r, "id")); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/seasons", http.StatusSeeOther)
+}
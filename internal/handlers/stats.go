@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mrjxtr-dev/score-board/internal/stats"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+)
+
+// GetStats shows the stats page for the current board.
+func (h *ScoreBoardHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	c := templates.Stats(stats.Compute(h.current()))
+	if err := templates.Layout(c, "Stats").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetStatsJSON returns the same numbers as the stats page.
+func (h *ScoreBoardHandler) GetStatsJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(stats.Compute(h.current()))
+}
+
+// standingsTeam is one row of GetStandingsJSON.
+type standingsTeam struct {
+	Rank  int            `json:"rank"`
+	Team  string         `json:"team"`
+	Color string         `json:"color"`
+	Key   string         `json:"key,omitempty"`
+	Total int            `json:"total"`
+	Games map[string]int `json:"games"`
+}
+
+type standingsResponse struct {
+	Board    string          `json:"board"`
+	Version  int             `json:"version"`
+	Finished bool            `json:"finished"`
+	Games    []string        `json:"games"`
+	Teams    []standingsTeam `json:"teams"`
+}
+
+// GetStandingsJSON returns the ranked teams with their colors, rapid entry
+// keys and game scores; the terminal client draws its table from it.
+func (h *ScoreBoardHandler) GetStandingsJSON(w http.ResponseWriter, r *http.Request) {
+	b := h.current()
+	keys := make(map[string]string)
+	for _, s := range templates.TeamShortcuts(b) {
+		keys[s.Team.TeamName] = s.Key
+	}
+	resp := standingsResponse{
+		Board:    b.BoardName,
+		Version:  b.Version,
+		Finished: b.Finished(),
+		Games:    templates.UniqueGameNames(b),
+		Teams:    []standingsTeam{},
+	}
+	for _, s := range b.Standings() {
+		row := standingsTeam{Rank: s.Rank, Team: s.Team, Total: s.Total, Key: keys[s.Team], Games: map[string]int{}}
+		for _, t := range b.Teams {
+			if t == nil || t.TeamName != s.Team {
+				continue
+			}
+			row.Color = t.TeamColor["color"]
+			for _, g := range t.Games {
+				row.Games[g.GameName] = g.Score()
+			}
+		}
+		resp.Teams = append(resp.Teams, row)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
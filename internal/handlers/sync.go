@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+const (
+	maxSyncEntries = 200
+	maxSyncIDLen   = 64
+)
+
+// syncEntry is a score queued in the browser while offline. The ID is
+// generated on the client and makes retries safe.
+type syncEntry struct {
+	ID    string `json:"id"`
+	Game  string `json:"game"`
+	Round string `json:"round"`
+	Score int    `json:"score"`
+}
+
+type syncRequest struct {
+	Entries []syncEntry `json:"entries"`
+}
+
+type syncResponse struct {
+	Results []store.SyncResult `json:"results"`
+}
+
+// PostTeamSync applies a batch of offline score entries for one team, in
+// order. Entries seen before get their original result back instead of
+// being applied again. An entry for a round that already holds a different
+// score is reported as a conflict and left alone.
+func (h *ScoreBoardHandler) PostTeamSync(w http.ResponseWriter, r *http.Request) {
+	teamParam, _ := url.PathUnescape(chi.URLParam(r, "team"))
+	b := h.edit()
+	var team *store.Team
+	for _, t := range b.Teams {
+		if t != nil && t.TeamName == teamParam {
+			team = t
+			break
+		}
+	}
+	if team == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req syncRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Entries) > maxSyncEntries {
+		http.Error(w, "too many entries; send at most "+strconv.Itoa(maxSyncEntries), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	now := time.Now()
+	resp := syncResponse{Results: make([]store.SyncResult, 0, len(req.Entries))}
+	var recorded []store.SyncResult
+	// An ID repeated within the batch counts as seen, same as one from an
+	// earlier sync
+	seen := make(map[string]store.SyncResult)
+	changed := false
+	for _, e := range req.Entries {
+		prev, ok := seen[e.ID]
+		if !ok {
+			prev, ok = h.syncLog.Get(e.ID)
+		}
+		if ok {
+			prev.Replayed = true
+			resp.Results = append(resp.Results, prev)
+			continue
+		}
+		res := applySyncEntry(team, e)
+		res.At = now
+		if res.Status == store.SyncApplied {
+			changed = true
+		}
+		resp.Results = append(resp.Results, res)
+		if e.ID != "" && len(e.ID) <= maxSyncIDLen {
+			recorded = append(recorded, res)
+			seen[e.ID] = res
+		}
+	}
+
+	// Log the entries before saving them: if the board can't be saved the
+	// log is taken back, and a retry applies them then. The other way
+	// round, a failure in between would apply them twice.
+	if err := h.syncLog.Record(recorded); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if changed {
+		if err := h.save(b); err != nil {
+			ids := make([]string, len(recorded))
+			for i, res := range recorded {
+				ids[i] = res.ID
+			}
+			if ferr := h.syncLog.Forget(ids); ferr != nil {
+				log.Printf("sync: taking back the log after a failed save: %v", ferr)
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// applySyncEntry checks one entry and, if it is fine, writes it to the team.
+func applySyncEntry(team *store.Team, e syncEntry) store.SyncResult {
+	res := store.SyncResult{
+		ID:    e.ID,
+		Team:  team.TeamName,
+		Game:  strings.TrimSpace(e.Game),
+		Round: strings.TrimSpace(e.Round),
+		Score: e.Score,
+	}
+	reject := func(msg string) store.SyncResult {
+		res.Status, res.Error = store.SyncRejected, msg
+		return res
+	}
+
+	if e.ID == "" || len(e.ID) > maxSyncIDLen {
+		return reject("id must be 1 to " + strconv.Itoa(maxSyncIDLen) + " characters")
+	}
+	errs := validate.Errors{}
+	errs.OptionalName("round", res.Round, validate.MaxRoundName)
+	errs.Score("score", strconv.Itoa(e.Score))
+	if errs.Has("round") {
+		return reject("round " + errs.Get("round"))
+	}
+	if errs.Has("score") {
+		return reject("score " + errs.Get("score"))
+	}
+	g := teamGame(team, res.Game)
+	if g == nil {
+		return reject("game does not exist")
+	}
+	if g.Rounds == nil {
+		g.Rounds = make(map[string]int)
+	}
+
+	if existing, ok := g.Rounds[res.Round]; ok && res.Round != "" {
+		if existing != e.Score {
+			res.Status, res.Existing = store.SyncConflict, &existing
+			return res
+		}
+		res.Status = store.SyncApplied
+		return res
+	}
+	if g.RoundsFull() {
+		return reject("round limit reached (" + strconv.Itoa(g.RoundLimit) + " rounds)")
+	}
+	if res.Round == "" {
+		res.Round = strconv.Itoa(templates.NextRoundForGame(*g))
+	}
+	g.Rounds[res.Round] = e.Score
+	res.Status = store.SyncApplied
+	return res
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+func TestTeamSyncAppliesEachIDOnce(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	r := chi.NewRouter()
+	r.With(h.Board.Writes).Post("/board/team/{team}/sync", h.Board.PostTeamSync)
+
+	sync := func(body string) syncResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/board/team/Red/sync", strings.NewReader(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("got %d: %s", w.Code, w.Body)
+		}
+		var resp syncResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	body := `{"entries":[
+		{"id":"a","game":"Darts","score":5},
+		{"id":"a","game":"Darts","score":5},
+		{"id":"b","game":"Darts","score":3}]}`
+	resp := sync(body)
+	if got := len(savedRounds(t, dir, "Red")); got != 2 {
+		t.Fatalf("db.json has %d rounds, want 2", got)
+	}
+	if res := resp.Results[1]; !res.Replayed || res.Status != store.SyncApplied {
+		t.Fatalf("repeated entry = %+v, want the first result replayed", res)
+	}
+
+	// A retry of the whole batch changes nothing
+	for _, res := range sync(body).Results {
+		if !res.Replayed {
+			t.Fatalf("retried entry %s wasn't replayed", res.ID)
+		}
+	}
+	if got := len(savedRounds(t, dir, "Red")); got != 2 {
+		t.Fatalf("db.json has %d rounds after a retry, want 2", got)
+	}
+}
+
+func TestTeamSyncReportsConflicts(t *testing.T) {
+	h, dir := newTestHandlers(t)
+	r := chi.NewRouter()
+	r.With(h.Board.Writes).Post("/board/team/{team}/sync", h.Board.PostTeamSync)
+
+	sync := func(body string) store.SyncResult {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/board/team/Red/sync", strings.NewReader(body)))
+		var resp syncResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || len(resp.Results) != 1 {
+			t.Fatalf("got %d %s", w.Code, w.Body)
+		}
+		return resp.Results[0]
+	}
+
+	// Two phones both fill round 1 while offline
+	if res := sync(`{"entries":[{"id":"phone1","game":"Darts","round":"1","score":5}]}`); res.Status != store.SyncApplied {
+		t.Fatalf("first phone: %+v, want applied", res)
+	}
+	res := sync(`{"entries":[{"id":"phone2","game":"Darts","round":"1","score":8}]}`)
+	if res.Status != store.SyncConflict || res.Existing == nil || *res.Existing != 5 {
+		t.Fatalf("second phone: %+v, want a conflict with 5", res)
+	}
+	// A score that did get through before its response was lost
+	if res := sync(`{"entries":[{"id":"phone3","game":"Darts","round":"1","score":5}]}`); res.Status != store.SyncApplied {
+		t.Fatalf("repeat of a saved score: %+v, want applied", res)
+	}
+	if rounds := savedRounds(t, dir, "Red"); len(rounds) != 1 || rounds["1"] != 5 {
+		t.Fatalf("db.json has rounds %v, want just 1: 5", rounds)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+// GetBoardTemplates lists saved board templates.
+func (h *ScoreBoardHandler) GetBoardTemplates(w http.ResponseWriter, r *http.Request) {
+	h.renderBoardTemplates(w, r, "", "")
+}
+
+func (h *ScoreBoardHandler) renderBoardTemplates(w http.ResponseWriter, r *http.Request, name, errMsg string) {
+	c := templates.BoardTemplates(h.boards.List(), h.current(), name, errMsg)
+	if err := templates.Layout(c, "Templates").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostBoardTemplate saves the current board's format as a template.
+func (h *ScoreBoardHandler) PostBoardTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("name"))
+	b := h.current()
+	errs := validate.Errors{}
+	errs.Name("name", name, validate.MaxBoardName)
+	if b == nil || len(b.Teams) == 0 {
+		errs.Add("name", "create a board first")
+	}
+	if errs.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderBoardTemplates(w, r, name, "template name "+errs.Get("name"))
+		return
+	}
+	if err := h.boards.Add(store.TemplateFromBoard(name, b)); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderBoardTemplates(w, r, name, err.Error())
+		return
+	}
+	http.Redirect(w, r, "/templates", http.StatusSeeOther)
+}
+
+// PostDeleteBoardTemplate removes a saved template.
+func (h *ScoreBoardHandler) PostDeleteBoardTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	if err := h.boards.Remove(strings.TrimSpace(r.FormValue("name"))); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/templates", http.StatusSeeOther)
+}
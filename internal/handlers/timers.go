@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/live"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+// maxTimerMinutes caps a game clock at three hours.
+const maxTimerMinutes = 180
+
+// publishTimers pushes the current timer states to live views.
+func (h *ScoreBoardHandler) publishTimers() {
+	if data, err := json.Marshal(h.current().TimerSnapshot(time.Now())); err == nil {
+		h.live.Publish(live.Event{Name: "timers", Data: string(data)})
+	}
+}
+
+// GetTimers shows the scorekeeper page for controlling game clocks.
+func (h *ScoreBoardHandler) GetTimers(w http.ResponseWriter, r *http.Request) {
+	h.renderTimers(w, r, templates.TimerForm{})
+}
+
+func (h *ScoreBoardHandler) renderTimers(w http.ResponseWriter, r *http.Request, f templates.TimerForm) {
+	c := templates.Timers(h.current(), f, time.Now())
+	if err := templates.Layout(c, "Timers").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetTimersJSON returns the timer states with the server clock, for clients
+// that need to resync.
+func (h *ScoreBoardHandler) GetTimersJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(h.current().TimerSnapshot(time.Now()))
+}
+
+// PostTimer applies a clock action (start, pause, resume, reset, duration)
+// to a game's timer.
+func (h *ScoreBoardHandler) PostTimer(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	gameName := strings.TrimSpace(r.FormValue("game_name"))
+	b := h.edit()
+	exists := false
+	for _, g := range templates.UniqueGameNames(b) {
+		if g == gameName {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		http.Error(w, "game does not exist; add it in Games", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	t := b.EnsureTimer(gameName)
+	switch chi.URLParam(r, "action") {
+	case "start":
+		t.Start(now)
+	case "pause":
+		t.Pause(now)
+	case "resume":
+		t.Resume(now)
+	case "reset":
+		t.Reset()
+	case "duration":
+		minutes := strings.TrimSpace(r.FormValue("minutes"))
+		f := templates.TimerForm{GameName: gameName, Minutes: minutes, Errors: validate.Errors{}}
+		v, ok := f.Errors.Int("minutes", minutes, 1, maxTimerMinutes)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			h.renderTimers(w, r, f)
+			return
+		}
+		t.SetDuration(time.Duration(v) * time.Minute)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := h.persist(b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.publishTimers()
+	http.Redirect(w, r, "/timers", http.StatusSeeOther)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/certs"
+	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/templates"
+	"github.com/skip2/go-qrcode"
+)
+
+// maxTokenHours caps how long a team link can stay valid (one week).
+const maxTokenHours = 24 * 7
+
+// teamLinks builds the shareable link for every issued team token.
+func (h *ScoreBoardHandler) teamLinks(r *http.Request) []templates.TeamLink {
+	tokens := h.tokens.List()
+	links := make([]templates.TeamLink, 0, len(tokens))
+	for _, tok := range tokens {
+		links = append(links, templates.TeamLink{
+			Token: tok,
+			URL:   h.teamLinkURL(r, h.tokens.Sign(tok), tok.Team),
+		})
+	}
+	return links
+}
+
+// teamLinkURL returns the absolute URL a captain opens to post scores. It
+// uses the host the admin is browsing on, so the link works from the same
+// network, but only if that's one of this server's own names: the Host
+// header is up to the client, and a forged one mustn't end up in a team
+// link. Anything else gets the configured address.
+func (h *ScoreBoardHandler) teamLinkURL(r *http.Request, token, team string) string {
+	base := h.cfg.LocalURL()
+	name := r.Host
+	if host, _, err := net.SplitHostPort(name); err == nil {
+		name = host
+	}
+	if h.linkHosts[strings.ToLower(strings.Trim(name, "[]"))] {
+		scheme := "http"
+		if h.cfg.TLS.Enabled() {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	return base + "/board/team/" + url.PathEscape(team) + "?token=" + url.QueryEscape(token)
+}
+
+// linkHosts are the host names team links may point at: the one the
+// server listens on or, listening on every interface, this machine's own
+// names and addresses, plus any extra TLS names.
+func linkHosts(cfg *config.Config) map[string]bool {
+	var names []string
+	host, _, _ := net.SplitHostPort(cfg.Addr)
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		names = certs.Hosts()
+	} else {
+		names = []string{host}
+	}
+	names = append(names, cfg.TLS.Hosts...)
+	hosts := make(map[string]bool, len(names))
+	for _, n := range names {
+		hosts[strings.ToLower(n)] = true
+	}
+	return hosts
+}
+
+// PostTeamToken issues a new scorekeeper link for a single team.
+func (h *ScoreBoardHandler) PostTeamToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	teamName := strings.TrimSpace(r.FormValue("team"))
+	hours, err := strconv.Atoi(strings.TrimSpace(r.FormValue("hours")))
+	if err != nil || hours < 1 || hours > maxTokenHours {
+		http.Error(w, "hours must be between 1 and 168", http.StatusBadRequest)
+		return
+	}
+	found := false
+	for _, t := range h.current().Teams {
+		if t != nil && t.TeamName == teamName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		http.Error(w, "team does not exist", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.tokens.Issue(teamName, time.Duration(hours)*time.Hour); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+// PostRevokeTeamToken revokes a team link so it stops working right away.
+func (h *ScoreBoardHandler) PostRevokeTeamToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	id := strings.TrimSpace(r.FormValue("id"))
+	if id == "" {
+		http.Error(w, "id required", http.StatusBadRequest)
+		return
+	}
+	if err := h.tokens.Revoke(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+// GetTeamTokenQR renders the team link as a QR code PNG.
+func (h *ScoreBoardHandler) GetTeamTokenQR(w http.ResponseWriter, r *http.Request) {
+	tok := h.tokens.Get(chi.URLParam(r, "id"))
+	if tok == nil {
+		http.NotFound(w, r)
+		return
+	}
+	png, err := qrcode.Encode(h.teamLinkURL(r, h.tokens.Sign(*tok), tok.Team), qrcode.Medium, 256)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(png)
+}
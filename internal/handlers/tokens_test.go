@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTeamLinkURLIgnoresForeignHost(t *testing.T) {
+	h, _ := newTestHandlers(t)
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"localhost:8080", "http://localhost:8080/board/team/Red?"},
+		{"127.0.0.1:8080", "http://127.0.0.1:8080/board/team/Red?"},
+		{"[::1]:8080", "http://[::1]:8080/board/team/Red?"},
+		{"evil.example:8080", "http://localhost:8080/board/team/Red?"},
+		{"evil.example", "http://localhost:8080/board/team/Red?"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/settings", nil)
+		r.Host = tt.host
+		if got := h.Board.teamLinkURL(r, "tok", "Red"); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("Host %q: got %s, want it to start with %s", tt.host, got, tt.want)
+		}
+	}
+}
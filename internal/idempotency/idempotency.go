@@ -0,0 +1,163 @@
+// Package idempotency makes repeated form posts and API calls safe: a
+// request carrying a key that was seen before gets the original response
+// back instead of being applied a second time.
+package idempotency
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/csrf"
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+const (
+	// FieldName is the hidden form field carrying the key.
+	FieldName = "idempotency_key"
+	// HeaderName lets scripts and API clients send the key.
+	HeaderName = "Idempotency-Key"
+	// ReplayedHeader marks a response that was replayed, not produced again.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLen = 128
+	// maxBody keeps huge responses (exports and the like) out of the store;
+	// those are not saved and a repeat simply runs again.
+	maxBody = 256 << 10
+)
+
+// Middleware replays saved responses for POST/PUT/PATCH/DELETE requests
+// whose key has been used before. Requests without a key go through as
+// usual.
+//
+// Keys are scoped to the browser (its CSRF token), the signed-in user and
+// the method and path, so one client can never get another's response.
+// Server errors and responses that set cookies (login, logout) are not
+// saved.
+func Middleware(keys *store.Idempotency) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if safeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := r.Header.Get(HeaderName)
+			if key == "" {
+				key = r.PostFormValue(FieldName)
+			}
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLen {
+				http.Error(w, "idempotency key is too long", http.StatusBadRequest)
+				return
+			}
+
+			scoped := scope(r, key)
+			if res, ok := keys.Begin(scoped); ok {
+				replay(w, res)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			var saved *store.SavedResponse
+			defer func() {
+				if err := keys.Finish(scoped, saved); err != nil {
+					log.Printf("idempotency: %v", err)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			if rec.keep() {
+				saved = &store.SavedResponse{
+					Status: rec.status,
+					Header: rec.Header().Clone(),
+					Body:   rec.body.Bytes(),
+					At:     time.Now(),
+				}
+			}
+		})
+	}
+}
+
+// NewKey returns a fresh random key for embedding in a form.
+func NewKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// scope ties a key to who sent it and where. It is hashed so the CSRF
+// token never ends up on disk.
+func scope(r *http.Request, key string) string {
+	user := ""
+	if u := auth.UserFromContext(r.Context()); u != nil {
+		user = u.Username
+	}
+	sum := sha256.Sum256([]byte(csrf.Token(r.Context()) + "\x00" + user + "\x00" + r.Method + " " + r.URL.Path + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+func replay(w http.ResponseWriter, res store.SavedResponse) {
+	for k, v := range res.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(res.Status)
+	_, _ = w.Write(res.Body)
+}
+
+// recorder passes a response through while keeping a copy of it.
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	tooBig   bool
+	streamed bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if !rec.tooBig {
+		if rec.body.Len()+len(p) > maxBody {
+			rec.tooBig = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(p)
+		}
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+func (rec *recorder) Flush() {
+	rec.streamed = true
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// keep reports whether the response is worth replaying.
+func (rec *recorder) keep() bool {
+	if rec.tooBig || rec.streamed || rec.status >= 500 {
+		return false
+	}
+	return len(rec.Header().Values("Set-Cookie")) == 0
+}
+
+func safeMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Package live fans out board updates to connected browsers using
+// server-sent events (SSE).
+package live
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keepAlive is how often an idle stream gets a comment so proxies and
+// browsers don't drop the connection.
+const keepAlive = 25 * time.Second
+
+// Event is one server-sent event.
+type Event struct {
+	Name string
+	Data string
+}
+
+// Broker keeps track of subscribers and broadcasts events to all of them.
+// Slow subscribers miss events rather than block the publisher.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[chan Event]struct{}
+	closed bool
+}
+
+// NewBroker creates an empty Broker.
+func NewBroker() *Broker {
+	return &Broker{
+		subs: make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe registers a new listener. Call the returned func to stop listening.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+	b.mu.Lock()
+	if b.closed {
+		close(ch)
+	} else {
+		b.subs[ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Publish sends an event to every subscriber.
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Close ends every stream and turns away new ones, so a shutting down
+// server isn't kept waiting by browsers that never hang up.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// ServeHTTP streams events to the client until it disconnects.
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events, unsubscribe := b.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes e in the text/event-stream format.
+func writeEvent(w http.ResponseWriter, e Event) error {
+	var sb strings.Builder
+	if e.Name != "" {
+		sb.WriteString("event: " + e.Name + "\n")
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+	sb.WriteString("\n")
+	_, err := fmt.Fprint(w, sb.String())
+	return err
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+)
+
+const teamTokenCookie = "sb_team_token"
+
+// teamAccess lets a request through when it carries a valid team token for
+// the {team} in the URL; otherwise it falls back to the usual role check.
+// A token passed as ?token= is remembered in a cookie so the page's forms
+// keep working without repeating it.
+func teamAccess(am *auth.Manager, role auth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fallback := am.RequireRole(role)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			team, _ := url.PathUnescape(chi.URLParam(r, "team"))
+
+			raw := r.URL.Query().Get("token")
+			fromQuery := raw != ""
+			if !fromQuery {
+				if c, err := r.Cookie(teamTokenCookie); err == nil {
+					raw = c.Value
+				}
+			}
+			if raw == "" {
+				fallback.ServeHTTP(w, r)
+				return
+			}
+
+			tok, err := am.Tokens.Verify(raw)
+			if err != nil || tok.Team != team {
+				fallback.ServeHTTP(w, r)
+				return
+			}
+			if fromQuery {
+				http.SetCookie(w, &http.Cookie{
+					Name:     teamTokenCookie,
+					Value:    raw,
+					Path:     "/board/team/",
+					Expires:  tok.ExpiresAt,
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
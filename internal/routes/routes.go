@@ -2,22 +2,43 @@
 package routes
 
 import (
+	"io"
+	"net"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/certs"
 	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/csrf"
 	"github.com/mrjxtr-dev/score-board/internal/handlers"
+	"github.com/mrjxtr-dev/score-board/internal/idempotency"
+	"github.com/mrjxtr-dev/score-board/internal/live"
 	"github.com/mrjxtr-dev/score-board/internal/store"
 )
 
-// SetupRoutes wires all HTTP routes. If staticFS is non-nil, it will serve
-// files from it at /static/; otherwise it falls back to the local ./static dir.
-func SetupRoutes(cfg *config.Config, db store.Database, staticFS http.FileSystem) *chi.Mux {
+// SetupRoutes wires all HTTP routes to the handlers, with live updates
+// streamed from lv. If staticFS is non-nil, it will serve files from it at
+// /static/; otherwise it falls back to the local ./static dir.
+//
+// The /display kiosk view is public. Viewers can look at the board,
+// scorekeepers can post scores and admins manage settings, games, users
+// and reset. Once an event is finished the board is read-only.
+func SetupRoutes(cfg *config.Config, h *handlers.Handlers, am *auth.Manager, lv *live.Broker, staticFS http.FileSystem) *chi.Mux {
 	r := chi.NewRouter()
-	setupGlobalMiddleware(r)
+	keys := store.LoadIdempotency(cfg.DataDir)
+	keys.TTL = cfg.Retention.Idempotency
+	setupGlobalMiddleware(r, am, keys)
 
-	h := handlers.NewHandlers(db)
+	viewer := am.RequireRole(auth.RoleViewer)
+	admin := am.RequireRole(auth.RoleAdmin)
+	// Routes that change the board take turns; open checks the board once
+	// it's their turn, so it goes after write
+	write := h.Board.Writes
+	open := h.Board.RequireOpenBoard
 
 	var fs http.FileSystem
 	if staticFS != nil {
@@ -27,38 +48,191 @@ func SetupRoutes(cfg *config.Config, db store.Database, staticFS http.FileSystem
 	}
 	fileserver := http.FileServer(fs)
 	r.Handle("/static/*", http.StripPrefix("/static/", fileserver))
+	// The service worker has to live at the root to control every page
+	r.Get("/sw.js", serviceWorker(fs))
+	if cfg.TLS.Auto {
+		r.Get("/ca.pem", caCert(cfg.DataDir))
+	}
 
 	r.Get("/", h.Home.GetHome)
 	r.Get("/about", h.Home.GetAbout)
 
-	// Games: list/add/rename/delete
-	r.Get("/games", h.Board.GetGames)
-	r.Post("/games", h.Board.PostGames)
-	r.Post("/games/rename", h.Board.PostRenameGame)
-	r.Post("/games/delete", h.Board.PostDeleteGame)
+	// Live updates (server-sent events) for the board and display views
+	r.Handle("/events", lv)
+
+	// Display: public read-only kiosk view
+	r.Get("/display", h.Board.GetDisplay)
+	r.Get("/display/panels", h.Board.GetDisplayPanels)
+	r.Get("/timers.json", h.Board.GetTimersJSON)
+
+	// Auth: first-run setup, login/logout
+	r.Get("/setup", h.Auth.GetSetup)
+	r.Post("/setup", h.Auth.PostSetup)
+	r.Get("/login", h.Auth.GetLogin)
+	r.Post("/login", h.Auth.PostLogin)
+	r.Post("/logout", h.Auth.PostLogout)
+
+	r.Group(func(r chi.Router) {
+		r.Use(admin)
+
+		// Users: list/add/delete
+		r.Get("/users", h.Auth.GetUsers)
+		r.Post("/users", h.Auth.PostUsers)
+		r.Post("/users/delete", h.Auth.PostDeleteUser)
+
+		// Games: list/add/rename/delete; locked once the event is finished
+		r.Get("/games", h.Board.GetGames)
+		r.Group(func(r chi.Router) {
+			r.Use(write, open)
+			r.Post("/games", h.Board.PostGames)
+			r.Post("/games/rename", h.Board.PostRenameGame)
+			r.Post("/games/delete", h.Board.PostDeleteGame)
+			r.Post("/games/schedule", h.Board.PostScheduleGame)
+			r.Post("/games/settings", h.Board.PostGameSettings)
+		})
+
+		// Templates: save the current board's format, list/delete
+		r.Get("/templates", h.Board.GetBoardTemplates)
+		r.Post("/templates", h.Board.PostBoardTemplate)
+		r.Post("/templates/delete", h.Board.PostDeleteBoardTemplate)
+
+		// Settings: edit/update board and reset
+		r.Get("/settings", h.Board.GetSettings)
+		r.With(write, open).Post("/settings", h.Board.PostSettings)
+		r.With(write).Post("/settings/reset", h.Board.PostResetBoard)
+		r.With(write).Post("/settings/finish", h.Board.PostFinishBoard)
+
+		// Team scorekeeper links: issue/revoke/QR
+		r.Post("/settings/tokens", h.Board.PostTeamToken)
+		r.Post("/settings/tokens/revoke", h.Board.PostRevokeTeamToken)
+		r.Get("/settings/tokens/{id}/qr.png", h.Board.GetTeamTokenQR)
+
+		// Seasons: create/update/delete
+		r.Post("/seasons", h.Season.PostSeason)
+		r.Post("/seasons/{id}", h.Season.PostUpdateSeason)
+		r.Post("/seasons/{id}/delete", h.Season.PostDeleteSeason)
+	})
+
+	// Schedule: timeline, board banner and calendar export
+	r.Group(func(r chi.Router) {
+		r.Use(viewer)
+		r.Get("/schedule", h.Board.GetSchedule)
+		r.Get("/schedule/now", h.Board.GetNowPlaying)
+		r.Get("/schedule.ics", h.Board.GetScheduleICS)
+	})
+
+	// Archive and seasons: finished events, their final results and the
+	// league standings across them
+	r.Group(func(r chi.Router) {
+		r.Use(viewer)
+		r.Get("/archive", h.Board.GetArchive)
+		r.Get("/archive/{id}", h.Board.GetArchivedBoard)
+		r.Get("/archive/{id}/results.{format}", h.Board.GetArchivedResults)
+		r.Get("/seasons", h.Season.GetSeasons)
+		r.Get("/seasons/{id}", h.Season.GetSeason)
+	})
+
+	// Game clocks: scorekeepers start/pause/resume/reset and set durations
+	r.Group(func(r chi.Router) {
+		r.Use(am.RequireRole(auth.RoleScorekeeper))
+		r.Get("/timers", h.Board.GetTimers)
+		r.With(write, open).Post("/timers/{action}", h.Board.PostTimer)
 
-	// Settings: edit/update board and reset
-	r.Get("/settings", h.Board.GetSettings)
-	r.Post("/settings", h.Board.PostSettings)
-	r.Post("/settings/reset", h.Board.PostResetBoard)
+		// Paper scoresheets: print/PDF and transcribe back in
+		r.Get("/scoresheets", h.Board.GetScoresheets)
+		r.Get("/scoresheets/{game}/print", h.Board.GetScoresheetPrint)
+		r.Get("/scoresheets/{game}/sheet.pdf", h.Board.GetScoresheetPDF)
+		r.Get("/scoresheets/{game}/entry", h.Board.GetScoresheetEntry)
+		r.With(write, open).Post("/scoresheets/{game}/entry", h.Board.PostScoresheetEntry)
+
+		// Rapid entry: keyboard-driven scoring with an all-or-nothing batch
+		r.Get("/rapid", h.Board.GetRapidEntry)
+		r.With(write, open).Post("/rapid/batch", h.Board.PostRapidBatch)
+	})
 
 	r.Route("/board", func(r chi.Router) {
-		r.Get("/", h.Board.GetScoreBoard)
-		r.Get("/new", h.Board.GetNewBoard)
-		r.Post("/new", h.Board.PostNewBoard)
-		// Team scores
-		r.Get("/team/{team}", h.Board.GetTeamScores)
-		r.Post("/team/{team}/scores", h.Board.PostTeamScores)
-		r.Post("/team/{team}/scores/bulk", h.Board.PostTeamScoresBulk)
-		r.Post("/team/{team}/scores/delete", h.Board.PostDeleteRound)
+		r.With(viewer).Get("/", h.Board.GetScoreBoard)
+		r.With(viewer).Get("/stats", h.Board.GetStats)
+		r.With(viewer).Get("/stats.json", h.Board.GetStatsJSON)
+		r.With(viewer).Get("/standings.json", h.Board.GetStandingsJSON)
+		r.With(viewer).Get("/chart.svg", h.Board.GetBoardChart)
+		r.With(viewer).Get("/results.{format}", h.Board.GetResults)
+		r.With(admin).Get("/new", h.Board.GetNewBoard)
+		r.With(admin, write).Post("/new", h.Board.PostNewBoard)
+		// Team scores; team links work in place of a login here
+		r.With(teamAccess(am, auth.RoleViewer)).Get("/team/{team}", h.Board.GetTeamScores)
+		r.With(teamAccess(am, auth.RoleViewer)).Get("/team/{team}/chart.svg", h.Board.GetTeamChart)
+		r.Group(func(r chi.Router) {
+			r.Use(teamAccess(am, auth.RoleScorekeeper), write, open)
+			r.Post("/team/{team}/scores", h.Board.PostTeamScores)
+			r.Post("/team/{team}/scores/bulk", h.Board.PostTeamScoresBulk)
+			r.Post("/team/{team}/scores/delete", h.Board.PostDeleteRound)
+			r.Post("/team/{team}/sync", h.Board.PostTeamSync)
+		})
 	})
 	return r
 }
 
-func setupGlobalMiddleware(r *chi.Mux) {
+// serviceWorker serves scripts/sw.js from the static files. Browsers only
+// let a worker control pages under its own path, so it can't sit in
+// /static/scripts like the rest.
+func serviceWorker(fs http.FileSystem) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := fs.Open("scripts/sw.js")
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		w.Header().Set("Content-Type", "application/javascript")
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = io.Copy(w, f)
+	}
+}
+
+// caCert serves the local CA certificate so phones and laptops can
+// download and trust it.
+func caCert(dataDir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-x509-ca-cert")
+		w.Header().Set("Content-Disposition", `attachment; filename="score-board-ca.crt"`)
+		http.ServeFile(w, r, filepath.Join(certs.Dir(dataDir), certs.CAFile))
+	}
+}
+
+// RedirectHTTPS sends plain HTTP visitors to the same page over HTTPS on
+// the server's port. The CA certificate stays downloadable over HTTP, since
+// a device has to trust it before HTTPS works.
+func RedirectHTTPS(cfg *config.Config) http.Handler {
+	_, port, _ := net.SplitHostPort(cfg.Addr)
+	mux := http.NewServeMux()
+	if cfg.TLS.Auto {
+		mux.HandleFunc("GET /ca.pem", caCert(cfg.DataDir))
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	})
+	return mux
+}
+
+func setupGlobalMiddleware(r *chi.Mux, am *auth.Manager, keys *store.Idempotency) {
 	r.Use(
 		middleware.Logger,
 		middleware.Recoverer,
 		middleware.Heartbeat("/ping"),
+		csrf.Middleware,
+		am.RequireSetup,
+		am.Middleware,
+		idempotency.Middleware(keys),
 	)
 }
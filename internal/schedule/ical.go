@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+const icsTime = "20060102T150405Z"
+
+// WriteICS writes the scheduled games as an iCalendar (RFC 5545) file.
+func WriteICS(w io.Writer, b *store.ScoreBoard, now time.Time) error {
+	var sb strings.Builder
+	line := func(s string) {
+		sb.WriteString(fold(s))
+		sb.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//score-board//schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escape(b.BoardName))
+	for _, e := range Timeline(b, now) {
+		g := e.Info
+		line("BEGIN:VEVENT")
+		line("UID:" + uid(b.BoardName, g.Game) + "@score-board")
+		line("DTSTAMP:" + now.UTC().Format(icsTime))
+		line("DTSTART:" + g.Start.UTC().Format(icsTime))
+		line("DTEND:" + g.End.UTC().Format(icsTime))
+		line("SUMMARY:" + escape(g.Game))
+		if g.Location != "" {
+			line("LOCATION:" + escape(g.Location))
+		}
+		line("DESCRIPTION:" + escape(b.BoardName))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// uid gives each game a stable id so calendar apps update events in place
+// when the schedule is re-imported.
+func uid(board, game string) string {
+	sum := sha1.Sum([]byte(board + "\x00" + game))
+	return hex.EncodeToString(sum[:10])
+}
+
+// escape escapes TEXT values per RFC 5545 section 3.3.11.
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// fold splits content lines longer than 75 octets, continuing them on the
+// next line with a leading space, without breaking UTF-8 sequences.
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var sb strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > limit {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += n
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+// Package schedule works out the event timeline from the games' time slots:
+// what is on now, what is up next, and an iCalendar export.
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+// Status is where a game sits relative to the current time.
+type Status string
+
+const (
+	Upcoming Status = "upcoming"
+	Live     Status = "live"
+	Done     Status = "done"
+)
+
+// Entry is one scheduled game on the timeline.
+type Entry struct {
+	Info   *store.GameInfo
+	Status Status
+}
+
+// Timeline returns the scheduled games ordered by start time.
+func Timeline(b *store.ScoreBoard, now time.Time) []Entry {
+	if b == nil {
+		return nil
+	}
+	entries := make([]Entry, 0, len(b.GameInfos))
+	for _, g := range b.GameInfos {
+		if !g.Scheduled() {
+			continue
+		}
+		st := Upcoming
+		switch {
+		case g.Live(now):
+			st = Live
+		case !now.Before(g.End):
+			st = Done
+		}
+		entries = append(entries, Entry{Info: g, Status: st})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Info.Start.Before(entries[j].Info.Start)
+	})
+	return entries
+}
+
+// NowPlaying returns the games whose slot covers now. Overlapping slots
+// (parallel games in different locations) all count.
+func NowPlaying(b *store.ScoreBoard, now time.Time) []*store.GameInfo {
+	var live []*store.GameInfo
+	for _, e := range Timeline(b, now) {
+		if e.Status == Live {
+			live = append(live, e.Info)
+		}
+	}
+	return live
+}
+
+// UpNext returns the next game to start after now, or nil.
+func UpNext(b *store.ScoreBoard, now time.Time) *store.GameInfo {
+	for _, e := range Timeline(b, now) {
+		if e.Status == Upcoming {
+			return e.Info
+		}
+	}
+	return nil
+}
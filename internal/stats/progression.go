@@ -0,0 +1,73 @@
+package stats
+
+import "github.com/mrjxtr-dev/score-board/internal/store"
+
+// Progression is each team's running total after every round, in play order.
+type Progression struct {
+	Labels []string       `json:"labels"` // "Start", then "<game> R<round>"
+	Games  []GameSpan     `json:"games"`
+	Teams  []TeamProgress `json:"teams"`
+}
+
+// GameSpan is the range of labels covering one game's rounds.
+type GameSpan struct {
+	Game  string `json:"game"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// TeamProgress is one team's running totals, one per label.
+type TeamProgress struct {
+	Team   string `json:"team"`
+	Color  string `json:"color"`
+	Totals []int  `json:"totals"`
+}
+
+// ComputeProgression works out the running totals for a board. Totals use
+// each game's scoring mode, so the last value is the team's TotalScore.
+func ComputeProgression(b *store.ScoreBoard) Progression {
+	p := Progression{Labels: []string{"Start"}, Games: []GameSpan{}, Teams: []TeamProgress{}}
+	if b == nil {
+		return p
+	}
+
+	// Rounds played so far per team and game, scored as the game is.
+	played := make(map[string]map[string]*store.Game)
+	var teams []*store.Team
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		teams = append(teams, t)
+		played[t.TeamName] = make(map[string]*store.Game)
+		for _, g := range t.Games {
+			played[t.TeamName][g.GameName] = &store.Game{
+				GameName: g.GameName,
+				Rounds:   make(map[string]int),
+				Scoring:  g.Scoring,
+			}
+		}
+		p.Teams = append(p.Teams, TeamProgress{Team: t.TeamName, Color: t.TeamColor["color"], Totals: []int{0}})
+	}
+
+	for _, rd := range collectRounds(b) {
+		idx := len(p.Labels)
+		p.Labels = append(p.Labels, rd.game+" R"+rd.name)
+		if n := len(p.Games); n > 0 && p.Games[n-1].Game == rd.game {
+			p.Games[n-1].End = idx
+		} else {
+			p.Games = append(p.Games, GameSpan{Game: rd.game, Start: idx, End: idx})
+		}
+		for i, t := range teams {
+			if v, ok := rd.scores[t.TeamName]; ok {
+				played[t.TeamName][rd.game].Rounds[rd.name] = v
+			}
+			total := 0
+			for _, g := range played[t.TeamName] {
+				total += g.Score()
+			}
+			p.Teams[i].Totals = append(p.Teams[i].Totals, total)
+		}
+	}
+	return p
+}
@@ -0,0 +1,297 @@
+// Package stats works out per-team and per-game numbers from a board's
+// round scores: averages, highs and lows, round-win streaks, game wins and
+// how the scores are spread out.
+package stats
+
+import (
+	"math"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+)
+
+// buckets is how many ranges the score distribution is split into.
+const buckets = 6
+
+// RoundScore is one team's score in one round.
+type RoundScore struct {
+	Team  string `json:"team,omitempty"`
+	Game  string `json:"game"`
+	Round string `json:"round"`
+	Score int    `json:"score"`
+}
+
+// Bucket is a score range in the distribution, inclusive on both ends.
+type Bucket struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// TeamStats sums up one team's rounds.
+type TeamStats struct {
+	Team          string      `json:"team"`
+	Color         string      `json:"color"`
+	Total         int         `json:"total"`
+	Rounds        int         `json:"rounds"`
+	Average       float64     `json:"average"`
+	High          *RoundScore `json:"high,omitempty"`
+	Low           *RoundScore `json:"low,omitempty"`
+	RoundWins     int         `json:"round_wins"`
+	LongestStreak int         `json:"longest_streak"`
+	CurrentStreak int         `json:"current_streak"`
+	GameWins      int         `json:"game_wins"`
+	// Distribution counts the team's round scores per bucket.
+	Distribution []int `json:"distribution"`
+}
+
+// GameStats sums up one game across teams.
+type GameStats struct {
+	Game    string      `json:"game"`
+	Rounds  int         `json:"rounds"`
+	Average float64     `json:"average"`
+	High    *RoundScore `json:"high,omitempty"`
+	// Winners are the teams with the best game score; more than one on a tie.
+	Winners []string `json:"winners"`
+}
+
+// Report is everything the stats page and JSON endpoint show.
+type Report struct {
+	Board   string      `json:"board"`
+	Teams   []TeamStats `json:"teams"`
+	Games   []GameStats `json:"games"`
+	Buckets []Bucket    `json:"buckets"`
+}
+
+// round is one (game, round) slot with every team's score in it.
+type round struct {
+	game   string
+	name   string
+	scores map[string]int // team -> score
+}
+
+// Compute builds the report for a board.
+func Compute(b *store.ScoreBoard) Report {
+	r := Report{Teams: []TeamStats{}, Games: []GameStats{}, Buckets: []Bucket{}}
+	if b == nil {
+		return r
+	}
+	r.Board = b.BoardName
+
+	rounds := collectRounds(b)
+	r.Buckets = makeBuckets(rounds)
+
+	byTeam := make(map[string]*TeamStats)
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		r.Teams = append(r.Teams, TeamStats{
+			Team:         t.TeamName,
+			Color:        t.TeamColor["color"],
+			Total:        t.TotalScore(),
+			Distribution: make([]int, len(r.Buckets)),
+		})
+	}
+	for i := range r.Teams {
+		byTeam[r.Teams[i].Team] = &r.Teams[i]
+	}
+
+	// Rounds in play order drive highs, lows, averages and streaks.
+	sums := make(map[string]int)
+	for _, rd := range rounds {
+		best, contested := roundBest(rd)
+		for _, t := range b.Teams {
+			if t == nil {
+				continue
+			}
+			score, played := rd.scores[t.TeamName]
+			if !played {
+				continue
+			}
+			ts := byTeam[t.TeamName]
+			rs := &RoundScore{Game: rd.game, Round: rd.name, Score: score}
+			ts.Rounds++
+			sums[ts.Team] += score
+			if ts.High == nil || score > ts.High.Score {
+				ts.High = rs
+			}
+			if ts.Low == nil || score < ts.Low.Score {
+				ts.Low = rs
+			}
+			if i := bucketIndex(r.Buckets, score); i >= 0 {
+				ts.Distribution[i]++
+			}
+			if !contested {
+				continue
+			}
+			if score == best {
+				ts.RoundWins++
+				ts.CurrentStreak++
+				ts.LongestStreak = max(ts.LongestStreak, ts.CurrentStreak)
+			} else {
+				ts.CurrentStreak = 0
+			}
+		}
+	}
+	for i := range r.Teams {
+		if r.Teams[i].Rounds > 0 {
+			r.Teams[i].Average = round1(float64(sums[r.Teams[i].Team]) / float64(r.Teams[i].Rounds))
+		}
+	}
+
+	for _, name := range gameNames(b) {
+		gs := gameStats(b, name, rounds)
+		for _, w := range gs.Winners {
+			byTeam[w].GameWins++
+		}
+		r.Games = append(r.Games, gs)
+	}
+	return r
+}
+
+// collectRounds lists every (game, round) slot in play order: games in
+// board order, rounds by number.
+func collectRounds(b *store.ScoreBoard) []round {
+	var rounds []round
+	for _, name := range gameNames(b) {
+		// Teams may not all have the same rounds, so sort the union.
+		all := store.Game{Rounds: make(map[string]int)}
+		scores := make(map[string]map[string]int)
+		for _, t := range b.Teams {
+			if t == nil {
+				continue
+			}
+			for _, g := range t.Games {
+				if g.GameName != name {
+					continue
+				}
+				for rn, v := range g.Rounds {
+					all.Rounds[rn] = 0
+					if scores[rn] == nil {
+						scores[rn] = make(map[string]int)
+					}
+					scores[rn][t.TeamName] = v
+				}
+			}
+		}
+		for _, rn := range all.RoundNames() {
+			rounds = append(rounds, round{game: name, name: rn, scores: scores[rn]})
+		}
+	}
+	return rounds
+}
+
+// roundBest returns the top score in a round and whether the round counts
+// for wins and streaks, which needs at least two teams.
+func roundBest(rd round) (int, bool) {
+	best, first := 0, true
+	for _, v := range rd.scores {
+		if first || v > best {
+			best, first = v, false
+		}
+	}
+	return best, len(rd.scores) >= 2
+}
+
+func gameNames(b *store.ScoreBoard) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			if g.GameName != "" && !seen[g.GameName] {
+				seen[g.GameName] = true
+				names = append(names, g.GameName)
+			}
+		}
+	}
+	return names
+}
+
+// gameStats works out a game's round count, average, high and winners.
+// Winners go by the game score, so scoring modes are respected.
+func gameStats(b *store.ScoreBoard, name string, rounds []round) GameStats {
+	gs := GameStats{Game: name, Winners: []string{}}
+	sum, n := 0, 0
+	for _, rd := range rounds {
+		if rd.game != name {
+			continue
+		}
+		gs.Rounds++
+		for _, t := range b.Teams {
+			if t == nil {
+				continue
+			}
+			v, ok := rd.scores[t.TeamName]
+			if !ok {
+				continue
+			}
+			sum += v
+			n++
+			if gs.High == nil || v > gs.High.Score {
+				gs.High = &RoundScore{Team: t.TeamName, Game: name, Round: rd.name, Score: v}
+			}
+		}
+	}
+	if n > 0 {
+		gs.Average = round1(float64(sum) / float64(n))
+	}
+
+	best, scored := 0, false
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			if g.GameName != name || len(g.Rounds) == 0 {
+				continue
+			}
+			switch v := g.Score(); {
+			case !scored || v > best:
+				best, scored = v, true
+				gs.Winners = []string{t.TeamName}
+			case v == best:
+				gs.Winners = append(gs.Winners, t.TeamName)
+			}
+		}
+	}
+	return gs
+}
+
+// makeBuckets splits the range of all round scores into equal-width
+// buckets. Small ranges get fewer, one-point-wide buckets.
+func makeBuckets(rounds []round) []Bucket {
+	lo, hi, found := 0, 0, false
+	for _, rd := range rounds {
+		for _, v := range rd.scores {
+			if !found {
+				lo, hi, found = v, v, true
+			}
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if !found {
+		return []Bucket{}
+	}
+	span := hi - lo + 1
+	width := (span + buckets - 1) / buckets
+	list := make([]Bucket, 0, buckets)
+	for start := lo; start <= hi; start += width {
+		list = append(list, Bucket{Min: start, Max: min(start+width-1, hi)})
+	}
+	return list
+}
+
+func bucketIndex(list []Bucket, v int) int {
+	for i, b := range list {
+		if v >= b.Min && v <= b.Max {
+			return i
+		}
+	}
+	return -1
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
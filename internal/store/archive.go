@@ -0,0 +1,147 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ArchivedBoard is a finished event: a frozen copy of the board and its
+// final standings.
+type ArchivedBoard struct {
+	ID         string      `json:"id"`
+	BoardName  string      `json:"board"`
+	FinishedAt time.Time   `json:"finished_at"`
+	Standings  []Standing  `json:"standings"`
+	Board      *ScoreBoard `json:"snapshot"`
+}
+
+// Winner returns the names of the teams that finished first.
+func (a ArchivedBoard) Winner() []string {
+	var names []string
+	for _, s := range a.Standings {
+		if s.Rank == 1 {
+			names = append(names, s.Team)
+		}
+	}
+	return names
+}
+
+// Finished reports whether the board was closed with "finish event".
+func (b *ScoreBoard) Finished() bool {
+	return b != nil && !b.FinishedAt.IsZero()
+}
+
+// Finish freezes the board: clocks stop and it is marked finished.
+func (b *ScoreBoard) Finish(now time.Time) {
+	for _, t := range b.Timers {
+		t.Pause(now)
+	}
+	b.FinishedAt = now
+}
+
+// Archive is the history of finished boards, persisted as JSON.
+type Archive struct {
+	mu       sync.RWMutex
+	filename string
+	boards   []ArchivedBoard
+}
+
+// LoadArchive boots the archive from archive.json in dataDir.
+func LoadArchive(dataDir string) *Archive {
+	const archiveFilename = "archive.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadArchiveFile(filepath.Join(dataDir, archiveFilename))
+}
+
+// LoadArchiveFile loads the archive from a JSON file.
+func LoadArchiveFile(filename string) *Archive {
+	a := &Archive{filename: filename}
+	if data, err := os.ReadFile(filename); err == nil {
+		_ = json.Unmarshal(data, &a.boards)
+	}
+	return a
+}
+
+// Add stores a deep copy of the board with its current standings.
+func (a *Archive) Add(b *ScoreBoard) (ArchivedBoard, error) {
+	// Round-trip through JSON so later edits can't reach the archived copy.
+	data, err := json.Marshal(b)
+	if err != nil {
+		return ArchivedBoard{}, err
+	}
+	snapshot := &ScoreBoard{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return ArchivedBoard{}, err
+	}
+	finished := b.FinishedAt
+	if finished.IsZero() {
+		finished = time.Now()
+	}
+
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+	entry := ArchivedBoard{
+		ID:         hex.EncodeToString(id),
+		BoardName:  b.BoardName,
+		FinishedAt: finished,
+		Standings:  b.Standings(),
+		Board:      snapshot,
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.boards = append(a.boards, entry)
+	if err := a.save(); err != nil {
+		a.boards = a.boards[:len(a.boards)-1]
+		return ArchivedBoard{}, err
+	}
+	return entry, nil
+}
+
+// Remove takes a board back out of the archive.
+func (a *Archive) Remove(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.boards = slices.DeleteFunc(a.boards, func(e ArchivedBoard) bool { return e.ID == id })
+	return a.save()
+}
+
+// List returns the archived boards, most recently finished first.
+func (a *Archive) List() []ArchivedBoard {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	list := append([]ArchivedBoard(nil), a.boards...)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].FinishedAt.After(list[j].FinishedAt)
+	})
+	return list
+}
+
+// Get returns the archived board with the given id.
+func (a *Archive) Get(id string) (ArchivedBoard, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, e := range a.boards {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return ArchivedBoard{}, false
+}
+
+// save writes the archive to disk. Callers must hold the write lock.
+func (a *Archive) save() error {
+	data, err := json.MarshalIndent(a.boards, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(a.filename, data, 0644)
+}
@@ -3,14 +3,27 @@ package store
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ScoreBoard represents a score board
 type ScoreBoard struct {
-	BoardName string  `json:"board"`
-	Teams     []*Team `json:"teams"`
+	BoardName  string      `json:"board"`
+	Teams      []*Team     `json:"teams"`
+	GameInfos  []*GameInfo `json:"game_info,omitempty"`
+	Timers     []*Timer    `json:"timers,omitempty"`
+	FinishedAt time.Time   `json:"finished_at,omitzero"`
+	ArchiveID  string      `json:"archive_id,omitempty"`
+	// Version goes up with every edit, so a form rendered from an older
+	// board can be told apart from a fresh one.
+	Version int `json:"version,omitempty"`
 }
 
 // Team represents a team
@@ -23,27 +36,42 @@ type Team struct {
 
 // Game represents a game
 type Game struct {
-	GameName string         `json:"game"`
-	Rounds   map[string]int `json:"rounds"`
+	GameName   string         `json:"game"`
+	Rounds     map[string]int `json:"rounds"`
+	Scoring    string         `json:"scoring,omitempty"`
+	RoundLimit int            `json:"round_limit,omitempty"`
+	// Changed is the board version at which this team's game last changed,
+	// so a form for one game isn't made stale by edits to the others.
+	Changed int `json:"changed,omitempty"`
 }
 
+// Scoring modes decide how a game's rounds turn into its score.
+const (
+	ScoringSum     = "sum"     // add every round (default)
+	ScoringBest    = "best"    // only the best round counts
+	ScoringAverage = "average" // rounded average of the rounds
+)
+
+// ScoringModes lists the valid scoring modes, default first.
+var ScoringModes = []string{ScoringSum, ScoringBest, ScoringAverage}
+
+// DBFilename is the board's file in the data directory.
+const DBFilename = "db.json"
+
 type Database interface {
 	SaveToJSON(filename string) error
 	GetBoard() *ScoreBoard
 }
 
-// LoadDB boots the single-app scoreboard from ./data/db.json.
+// LoadDB boots the single-app scoreboard from db.json in dataDir.
 // If it's missing or unreadable, it spins up a default board and saves it.
-func LoadDB() Database {
-	const dataDir = "./data"
-	const dbFilename = "db.json"
-
+func LoadDB(dataDir string) Database {
 	// Ensure data directory exists; if it fails, still return an in-memory default
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return NewBoard("Default Board")
 	}
 
-	fullpath := filepath.Join(dataDir, dbFilename)
+	fullpath := filepath.Join(dataDir, DBFilename)
 
 	// If no db file yet, create one with a default board
 	if _, err := os.Stat(fullpath); os.IsNotExist(err) {
@@ -99,6 +127,89 @@ func (b *ScoreBoard) AddTeam(team *Team) {
 	b.Teams = append(b.Teams, team)
 }
 
+// BlankGames returns the board's games with no rounds, for a new team.
+// Every team has the same games, so the first team's list is used.
+func (b *ScoreBoard) BlankGames() []Game {
+	var games []Game
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			games = append(games, Game{
+				GameName:   g.GameName,
+				Rounds:     make(map[string]int),
+				Scoring:    g.Scoring,
+				RoundLimit: g.RoundLimit,
+			})
+		}
+		break
+	}
+	return games
+}
+
+// Clone returns a deep copy of the board that can be changed without
+// touching the original.
+func (b *ScoreBoard) Clone() *ScoreBoard {
+	c := *b
+	c.Teams = make([]*Team, len(b.Teams))
+	for i, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		ct := *t
+		ct.TeamColor = maps.Clone(t.TeamColor)
+		ct.Members = slices.Clone(t.Members)
+		ct.Games = slices.Clone(t.Games)
+		for j := range ct.Games {
+			ct.Games[j].Rounds = maps.Clone(ct.Games[j].Rounds)
+		}
+		c.Teams[i] = &ct
+	}
+	c.GameInfos = make([]*GameInfo, len(b.GameInfos))
+	for i, g := range b.GameInfos {
+		if g != nil {
+			cg := *g
+			c.GameInfos[i] = &cg
+		}
+	}
+	c.Timers = make([]*Timer, len(b.Timers))
+	for i, t := range b.Timers {
+		if t != nil {
+			ct := *t
+			c.Timers[i] = &ct
+		}
+	}
+	return &c
+}
+
+// MarkChanged stamps every game whose rounds or scoring differ from prev
+// with the board's version. Games new since prev count as changed.
+func (b *ScoreBoard) MarkChanged(prev *ScoreBoard) {
+	old := make(map[string]*Team)
+	if prev != nil {
+		old = teamsByName(prev)
+	}
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		pt := old[t.TeamName]
+		for i := range t.Games {
+			g := &t.Games[i]
+			var pg *Game
+			if pt != nil {
+				if j := slices.IndexFunc(pt.Games, func(o Game) bool { return o.GameName == g.GameName }); j >= 0 {
+					pg = &pt.Games[j]
+				}
+			}
+			if pg == nil || !maps.Equal(pg.Rounds, g.Rounds) || pg.Scoring != g.Scoring || pg.RoundLimit != g.RoundLimit {
+				g.Changed = b.Version
+			}
+		}
+	}
+}
+
 // RemoveTeam removes a given team from the board
 func (b *ScoreBoard) RemoveTeam(team *Team) {
 	newTeams := make([]*Team, 0, len(b.Teams))
@@ -135,29 +246,82 @@ func (b *ScoreBoard) EditTeam(oldTeam, updates *Team) {
 
 // SaveToJSON saves the current board to a JSON file
 func (b *ScoreBoard) SaveToJSON(filename string) error {
-	file, err := os.Create(filename)
+	data, err := json.MarshalIndent(b, "", "  ")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(b); err != nil {
-		return err
-	}
-
-	return nil
+	return WriteFile(filename, append(data, '\n'), 0644)
 }
 
-// TotalScore returns the sum of all round scores across all games for this team.
+// TotalScore returns the sum of all game scores for this team.
 // If there are no games or rounds, it simply returns 0.
 func (t *Team) TotalScore() int {
 	total := 0
 	for _, g := range t.Games {
+		total += g.Score()
+	}
+	return total
+}
+
+// Score turns the game's rounds into a single score using its scoring mode.
+func (g Game) Score() int {
+	if len(g.Rounds) == 0 {
+		return 0
+	}
+	switch g.Scoring {
+	case ScoringBest:
+		first := true
+		best := 0
+		for _, v := range g.Rounds {
+			if first || v > best {
+				best, first = v, false
+			}
+		}
+		return best
+	case ScoringAverage:
+		sum := 0
+		for _, v := range g.Rounds {
+			sum += v
+		}
+		n := len(g.Rounds)
+		// Round half away from zero.
+		if sum < 0 {
+			return -((-sum*2 + n) / (2 * n))
+		}
+		return (sum*2 + n) / (2 * n)
+	default:
+		total := 0
 		for _, v := range g.Rounds {
 			total += v
 		}
+		return total
 	}
-	return total
+}
+
+// RoundNames returns the game's rounds in play order: numbered rounds by
+// number, then any named ones alphabetically.
+func (g Game) RoundNames() []string {
+	names := make([]string, 0, len(g.Rounds))
+	for rn := range g.Rounds {
+		names = append(names, rn)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, errA := strconv.Atoi(strings.TrimSpace(names[i]))
+		b, errB := strconv.Atoi(strings.TrimSpace(names[j]))
+		switch {
+		case errA == nil && errB == nil:
+			return a < b
+		case errA == nil:
+			return true
+		case errB == nil:
+			return false
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// RoundsFull reports whether the game has hit its round limit (0 = no limit).
+func (g Game) RoundsFull() bool {
+	return g.RoundLimit > 0 && len(g.Rounds) >= g.RoundLimit
 }
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFile replaces filename with data in one step: it writes a temp file
+// next to it, syncs it and renames it over the old one, so a crash or a
+// shutdown in the middle of a save leaves the old file or the new one, never
+// half of each.
+func WriteFile(filename string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp, filename)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package store
+
+import "time"
+
+// GameInfo holds board-level details about a game that aren't per-team:
+// when and where it is played.
+type GameInfo struct {
+	Game     string    `json:"game"`
+	Start    time.Time `json:"start,omitzero"`
+	End      time.Time `json:"end,omitzero"`
+	Location string    `json:"location,omitempty"`
+}
+
+// Scheduled reports whether the game has a time slot.
+func (g *GameInfo) Scheduled() bool {
+	return g != nil && !g.Start.IsZero() && !g.End.IsZero()
+}
+
+// Live reports whether the game's slot covers now.
+func (g *GameInfo) Live(now time.Time) bool {
+	return g.Scheduled() && !now.Before(g.Start) && now.Before(g.End)
+}
+
+// GameInfo returns the details for a game, or nil if it has none.
+func (b *ScoreBoard) GameInfo(game string) *GameInfo {
+	for _, g := range b.GameInfos {
+		if g != nil && g.Game == game {
+			return g
+		}
+	}
+	return nil
+}
+
+// EnsureGameInfo returns the details for a game, creating an empty entry
+// if needed.
+func (b *ScoreBoard) EnsureGameInfo(game string) *GameInfo {
+	if g := b.GameInfo(game); g != nil {
+		return g
+	}
+	g := &GameInfo{Game: game}
+	b.GameInfos = append(b.GameInfos, g)
+	return g
+}
+
+// RenameGameInfo moves a game's details to its new name.
+func (b *ScoreBoard) RenameGameInfo(oldName, newName string) {
+	if g := b.GameInfo(oldName); g != nil {
+		g.Game = newName
+	}
+}
+
+// RemoveGameInfo drops a game's details.
+func (b *ScoreBoard) RemoveGameInfo(game string) {
+	kept := make([]*GameInfo, 0, len(b.GameInfos))
+	for _, g := range b.GameInfos {
+		if g != nil && g.Game != game {
+			kept = append(kept, g)
+		}
+	}
+	b.GameInfos = kept
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxHistory is how many past versions of the board are kept around.
+const maxHistory = 50
+
+// History keeps recent versions of the board in memory so a stale form can
+// be told what changed since it was loaded.
+type History struct {
+	mu       sync.Mutex
+	versions map[int]*ScoreBoard
+	order    []int
+}
+
+// NewHistory returns an empty history.
+func NewHistory() *History {
+	return &History{versions: make(map[int]*ScoreBoard)}
+}
+
+// Remember stores a copy of the board under its version.
+func (h *History) Remember(b *ScoreBoard) {
+	if b == nil {
+		return
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return
+	}
+	snapshot := &ScoreBoard{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.versions[b.Version]; !ok {
+		h.order = append(h.order, b.Version)
+	}
+	h.versions[b.Version] = snapshot
+	for len(h.order) > maxHistory {
+		delete(h.versions, h.order[0])
+		h.order = h.order[1:]
+	}
+}
+
+// Get returns the board as it was at a version, if still known.
+func (h *History) Get(version int) (*ScoreBoard, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b, ok := h.versions[version]
+	return b, ok
+}
+
+// Changes lists, in plain words, how the board went from old to cur.
+// Timers are left out; they change all the time and don't make a form
+// stale.
+func Changes(old, cur *ScoreBoard) []string {
+	var out []string
+	if old.BoardName != cur.BoardName {
+		out = append(out, fmt.Sprintf("Board renamed from %q to %q", old.BoardName, cur.BoardName))
+	}
+	if !old.Finished() && cur.Finished() {
+		out = append(out, "The event was finished")
+	}
+
+	oldTeams, curTeams := teamsByName(old), teamsByName(cur)
+	for _, t := range cur.Teams {
+		if t != nil && oldTeams[t.TeamName] == nil {
+			out = append(out, fmt.Sprintf("Team %q added", t.TeamName))
+		}
+	}
+	for _, t := range old.Teams {
+		if t != nil && curTeams[t.TeamName] == nil {
+			out = append(out, fmt.Sprintf("Team %q removed", t.TeamName))
+		}
+	}
+
+	// Games are the same on every team, so they're compared once
+	oldGames, curGames := gamesByName(old), gamesByName(cur)
+	for _, name := range sortedKeys(curGames) {
+		g := curGames[name]
+		prev, ok := oldGames[name]
+		switch {
+		case !ok:
+			out = append(out, fmt.Sprintf("Game %q added", name))
+		case prev.Scoring != g.Scoring || prev.RoundLimit != g.RoundLimit:
+			out = append(out, fmt.Sprintf("Scoring or round limit of %q changed", name))
+		}
+		if !sameInfo(old.GameInfo(name), cur.GameInfo(name)) {
+			out = append(out, fmt.Sprintf("Schedule of %q changed", name))
+		}
+	}
+	for _, name := range sortedKeys(oldGames) {
+		if _, ok := curGames[name]; !ok {
+			out = append(out, fmt.Sprintf("Game %q removed", name))
+		}
+	}
+
+	for _, t := range cur.Teams {
+		if t == nil || oldTeams[t.TeamName] == nil {
+			continue
+		}
+		prev := oldTeams[t.TeamName]
+		if !slices.Equal(prev.Members, t.Members) {
+			out = append(out, fmt.Sprintf("Members of %q changed", t.TeamName))
+		}
+		if prev.TeamColor["color"] != t.TeamColor["color"] {
+			out = append(out, fmt.Sprintf("Color of %q changed", t.TeamName))
+		}
+		for _, g := range t.Games {
+			for _, pg := range prev.Games {
+				if pg.GameName == g.GameName {
+					out = append(out, roundChanges(t.TeamName, pg, g)...)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// roundChanges describes added, edited and deleted rounds of one game.
+func roundChanges(team string, old, cur Game) []string {
+	var out []string
+	prefix := team + " · " + old.GameName + " round "
+	for _, rn := range cur.RoundNames() {
+		sc := cur.Rounds[rn]
+		prev, ok := old.Rounds[rn]
+		if !ok {
+			out = append(out, fmt.Sprintf("%s%s added: %d", prefix, rn, sc))
+		} else if prev != sc {
+			out = append(out, fmt.Sprintf("%s%s changed: %d → %d", prefix, rn, prev, sc))
+		}
+	}
+	for _, rn := range old.RoundNames() {
+		if _, ok := cur.Rounds[rn]; !ok {
+			out = append(out, fmt.Sprintf("%s%s deleted (was %d)", prefix, rn, old.Rounds[rn]))
+		}
+	}
+	return out
+}
+
+func teamsByName(b *ScoreBoard) map[string]*Team {
+	m := make(map[string]*Team, len(b.Teams))
+	for _, t := range b.Teams {
+		if t != nil {
+			m[t.TeamName] = t
+		}
+	}
+	return m
+}
+
+func gamesByName(b *ScoreBoard) map[string]Game {
+	m := make(map[string]Game)
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			if _, ok := m[g.GameName]; !ok {
+				m[g.GameName] = g
+			}
+		}
+	}
+	return m
+}
+
+func sameInfo(a, b *GameInfo) bool {
+	var x, y GameInfo
+	if a != nil {
+		x = *a
+	}
+	if b != nil {
+		y = *b
+	}
+	return x.Start.Equal(y.Start) && x.End.Equal(y.End) && x.Location == y.Location
+}
+
+func sortedKeys(m map[string]Game) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	return keys
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultIdempotencyTTL is how long a saved response can be replayed. It
+	// only has to outlive retries and double taps, not whole events.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// maxIdempotencyKeys caps the file; the oldest responses go first.
+	maxIdempotencyKeys = 2000
+)
+
+// SavedResponse is a response kept so a repeated request can get it again.
+type SavedResponse struct {
+	Status int                 `json:"status"`
+	Header map[string][]string `json:"header,omitempty"`
+	Body   []byte              `json:"body,omitempty"`
+	At     time.Time           `json:"at"`
+}
+
+// Idempotency remembers the responses to recent mutating requests by their
+// idempotency key, so a request sent twice is only applied once.
+type Idempotency struct {
+	mu       sync.Mutex
+	filename string
+	saved    map[string]SavedResponse
+	running  map[string]chan struct{}
+
+	// TTL is how long responses are kept; set it before serving requests.
+	TTL time.Duration
+}
+
+// LoadIdempotency boots the key store from idempotency.json in dataDir.
+func LoadIdempotency(dataDir string) *Idempotency {
+	const keysFilename = "idempotency.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadIdempotencyFile(filepath.Join(dataDir, keysFilename))
+}
+
+// LoadIdempotencyFile loads saved responses from a JSON file.
+func LoadIdempotencyFile(filename string) *Idempotency {
+	s := &Idempotency{
+		filename: filename,
+		saved:    make(map[string]SavedResponse),
+		running:  make(map[string]chan struct{}),
+		TTL:      DefaultIdempotencyTTL,
+	}
+	if data, err := os.ReadFile(filename); err == nil {
+		_ = json.Unmarshal(data, &s.saved)
+	}
+	return s
+}
+
+// Begin claims a key. If a response was already saved for it, that is
+// returned with true and nothing should run. If a request with the same key
+// is still running, Begin waits for it to finish first. Otherwise the caller
+// owns the key and must call Finish.
+func (s *Idempotency) Begin(key string) (SavedResponse, bool) {
+	for {
+		s.mu.Lock()
+		if res, ok := s.saved[key]; ok && time.Since(res.At) < s.TTL {
+			s.mu.Unlock()
+			return res, true
+		}
+		wait, busy := s.running[key]
+		if !busy {
+			s.running[key] = make(chan struct{})
+			s.mu.Unlock()
+			return SavedResponse{}, false
+		}
+		s.mu.Unlock()
+		<-wait
+	}
+}
+
+// Finish releases a key claimed with Begin. A non-nil res is saved for
+// replay; with nil the key is simply freed and the next request with it
+// runs as normal.
+func (s *Idempotency) Finish(key string, res *SavedResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if wait, ok := s.running[key]; ok {
+		close(wait)
+		delete(s.running, key)
+	}
+	if res == nil {
+		return nil
+	}
+	s.saved[key] = *res
+	s.prune()
+	return s.save()
+}
+
+// prune drops expired responses and, past the cap, the oldest ones.
+// Callers must hold the lock.
+func (s *Idempotency) prune() {
+	cutoff := time.Now().Add(-s.TTL)
+	for k, res := range s.saved {
+		if res.At.Before(cutoff) {
+			delete(s.saved, k)
+		}
+	}
+	if len(s.saved) <= maxIdempotencyKeys {
+		return
+	}
+	keys := make([]string, 0, len(s.saved))
+	for k := range s.saved {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return s.saved[keys[i]].At.Before(s.saved[keys[j]].At) })
+	for _, k := range keys[:len(keys)-maxIdempotencyKeys] {
+		delete(s.saved, k)
+	}
+}
+
+// save writes responses to disk. Callers must hold the lock.
+func (s *Idempotency) save() error {
+	data, err := json.MarshalIndent(s.saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(s.filename, data, 0644)
+}
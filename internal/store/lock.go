@@ -0,0 +1,49 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LockFilename is the lock file in the data directory.
+const LockFilename = "score-board.lock"
+
+// ErrLocked means another process is using the data directory.
+var ErrLocked = errors.New("the data directory is in use")
+
+// Lock is held on a data directory by whoever is changing it: the server
+// for as long as it runs, or a command that edits the files. The server
+// keeps the board in memory and writes it back on every change, so anyone
+// else writing alongside it would be overwritten. The operating system
+// drops the lock if the process dies, so it never goes stale.
+type Lock struct {
+	f *os.File
+}
+
+// LockDataDir takes the lock on dataDir. If someone else holds it, the
+// error wraps ErrLocked.
+func LockDataDir(dataDir string) (*Lock, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dataDir, LockFilename)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, ErrLocked) {
+			return nil, fmt.Errorf("%w: %s", ErrLocked, dataDir)
+		}
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+	return &Lock{f: f}, nil
+}
+
+// Unlock lets the next process in. The file is left behind for it.
+func (l *Lock) Unlock() error {
+	return l.f.Close()
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrSeasonNotFound = errors.New("season not found")
+
+// DefaultPoints is the points-per-placement used for new seasons.
+var DefaultPoints = []int{10, 6, 4, 2}
+
+// Season groups finished boards (e.g. one per week) into a league with
+// cumulative standings.
+type Season struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Points    []int     `json:"points"`
+	Boards    []string  `json:"boards,omitempty"` // archive IDs
+	CreatedAt time.Time `json:"created_at"`
+
+	// Aliases maps another name a team played under to its season name,
+	// e.g. after a rename or a typo on one week's board.
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// SeasonStanding is one team's line on the season leaderboard.
+type SeasonStanding struct {
+	Team       string `json:"team"`
+	Points     int    `json:"points"`
+	Events     int    `json:"events"`
+	Wins       int    `json:"wins"`
+	TotalScore int    `json:"total_score"`
+	Rank       int    `json:"rank"`
+	// Placements holds the team's rank per board in season order; 0 means
+	// the team didn't play that one.
+	Placements []int `json:"placements"`
+}
+
+// teamKey is how team names are matched across boards: case, surrounding
+// and repeated spaces don't matter.
+func teamKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// TeamName returns the season name for a team as it appears on a board.
+func (s Season) TeamName(name string) string {
+	key := teamKey(name)
+	for alias, team := range s.Aliases {
+		if teamKey(alias) == key {
+			return team
+		}
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// PointsFor returns the points a placement earns. Tied teams share a rank
+// and so get the same points.
+func (s Season) PointsFor(rank int) int {
+	if rank < 1 || rank > len(s.Points) {
+		return 0
+	}
+	return s.Points[rank-1]
+}
+
+// Includes reports whether the archived board is part of the season.
+func (s Season) Includes(id string) bool {
+	return slices.Contains(s.Boards, id)
+}
+
+// Standings adds up the season's boards. Boards are passed in season order;
+// ones missing from the archive are skipped. Teams are ranked by points,
+// then wins, then total score.
+func (s Season) Standings(boards []ArchivedBoard) []SeasonStanding {
+	byKey := make(map[string]*SeasonStanding)
+	var order []string
+	for i, b := range boards {
+		for _, st := range b.Standings {
+			name := s.TeamName(st.Team)
+			key := teamKey(name)
+			row, ok := byKey[key]
+			if !ok {
+				row = &SeasonStanding{Team: name, Placements: make([]int, len(boards))}
+				byKey[key] = row
+				order = append(order, key)
+			}
+			if row.Placements[i] != 0 {
+				// Two teams on one board mapped to the same season team;
+				// keep the better placement.
+				continue
+			}
+			row.Placements[i] = st.Rank
+			row.Events++
+			row.Points += s.PointsFor(st.Rank)
+			row.TotalScore += st.Total
+			if st.Rank == 1 {
+				row.Wins++
+			}
+		}
+	}
+
+	list := make([]SeasonStanding, 0, len(order))
+	for _, key := range order {
+		list = append(list, *byKey[key])
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.TotalScore > b.TotalScore
+	})
+	for i := range list {
+		if i > 0 && list[i].Points == list[i-1].Points && list[i].Wins == list[i-1].Wins && list[i].TotalScore == list[i-1].TotalScore {
+			list[i].Rank = list[i-1].Rank
+		} else {
+			list[i].Rank = i + 1
+		}
+	}
+	return list
+}
+
+// Seasons is the season store, persisted as JSON.
+type Seasons struct {
+	mu       sync.RWMutex
+	filename string
+	seasons  []Season
+}
+
+// LoadSeasons boots the season store from seasons.json in dataDir.
+func LoadSeasons(dataDir string) *Seasons {
+	const seasonsFilename = "seasons.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadSeasonsFile(filepath.Join(dataDir, seasonsFilename))
+}
+
+// LoadSeasonsFile loads the season store from a JSON file.
+func LoadSeasonsFile(filename string) *Seasons {
+	s := &Seasons{filename: filename}
+	if data, err := os.ReadFile(filename); err == nil {
+		_ = json.Unmarshal(data, &s.seasons)
+	}
+	return s
+}
+
+// List returns the seasons, newest first.
+func (s *Seasons) List() []Season {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := append([]Season(nil), s.seasons...)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Get returns the season with the given id.
+func (s *Seasons) Get(id string) (Season, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, season := range s.seasons {
+		if season.ID == id {
+			return season, true
+		}
+	}
+	return Season{}, false
+}
+
+// Add creates a season and returns it with its new id.
+func (s *Seasons) Add(season Season) (Season, error) {
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+	season.ID = hex.EncodeToString(id)
+	season.CreatedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seasons = append(s.seasons, season)
+	return season, s.save()
+}
+
+// Update replaces a season, keeping its id and creation time.
+func (s *Seasons) Update(season Season) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.seasons {
+		if s.seasons[i].ID == season.ID {
+			season.CreatedAt = s.seasons[i].CreatedAt
+			s.seasons[i] = season
+			return s.save()
+		}
+	}
+	return ErrSeasonNotFound
+}
+
+// Remove deletes a season. Its boards stay in the archive.
+func (s *Seasons) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seasons = slices.DeleteFunc(s.seasons, func(season Season) bool {
+		return season.ID == id
+	})
+	return s.save()
+}
+
+// save writes the store to disk. Callers must hold the write lock.
+func (s *Seasons) save() error {
+	data, err := json.MarshalIndent(s.seasons, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(s.filename, data, 0644)
+}
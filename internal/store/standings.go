@@ -0,0 +1,88 @@
+package store
+
+import "sort"
+
+// Standing is a team's total and rank at one point in time.
+type Standing struct {
+	Team  string `json:"team"`
+	Total int    `json:"total"`
+	Rank  int    `json:"rank"`
+}
+
+// TeamDelta describes how a team's total and rank changed between two
+// snapshots of the standings.
+type TeamDelta struct {
+	Team      string `json:"team"`
+	PrevTotal int    `json:"prev_total"`
+	Total     int    `json:"total"`
+	Delta     int    `json:"delta"`
+	PrevRank  int    `json:"prev_rank"`
+	Rank      int    `json:"rank"`
+}
+
+// Standings ranks the teams by total score, highest first. Tied teams share
+// a rank (1, 2, 2, 4) and keep their board order.
+func (b *ScoreBoard) Standings() []Standing {
+	if b == nil {
+		return nil
+	}
+	list := make([]Standing, 0, len(b.Teams))
+	for _, t := range b.Teams {
+		if t != nil {
+			list = append(list, Standing{Team: t.TeamName, Total: t.TotalScore()})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Total > list[j].Total
+	})
+	for i := range list {
+		if i > 0 && list[i].Total == list[i-1].Total {
+			list[i].Rank = list[i-1].Rank
+		} else {
+			list[i].Rank = i + 1
+		}
+	}
+	return list
+}
+
+// DiffStandings returns the teams whose total or rank changed from prev to
+// next. Teams that only exist on one side are left out; see SameTeams.
+func DiffStandings(prev, next []Standing) []TeamDelta {
+	before := make(map[string]Standing, len(prev))
+	for _, s := range prev {
+		before[s.Team] = s
+	}
+	var deltas []TeamDelta
+	for _, s := range next {
+		p, ok := before[s.Team]
+		if !ok || (p.Total == s.Total && p.Rank == s.Rank) {
+			continue
+		}
+		deltas = append(deltas, TeamDelta{
+			Team:      s.Team,
+			PrevTotal: p.Total,
+			Total:     s.Total,
+			Delta:     s.Total - p.Total,
+			PrevRank:  p.Rank,
+			Rank:      s.Rank,
+		})
+	}
+	return deltas
+}
+
+// SameTeams reports whether both snapshots list the same set of teams.
+func SameTeams(prev, next []Standing) bool {
+	if len(prev) != len(next) {
+		return false
+	}
+	seen := make(map[string]bool, len(prev))
+	for _, s := range prev {
+		seen[s.Team] = true
+	}
+	for _, s := range next {
+		if !seen[s.Team] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Outcomes of an offline score entry.
+const (
+	SyncApplied  = "applied"  // the score was saved
+	SyncConflict = "conflict" // the round already has a different score
+	SyncRejected = "rejected" // invalid entry, unknown game or round limit
+)
+
+// DefaultSyncLogTTL is how long applied entry IDs are remembered. Phones
+// that stay offline longer than this are not a case we need to handle.
+const DefaultSyncLogTTL = 30 * 24 * time.Hour
+
+// SyncResult is what happened to one offline entry.
+type SyncResult struct {
+	ID       string    `json:"id"`
+	Status   string    `json:"status"`
+	Team     string    `json:"team"`
+	Game     string    `json:"game"`
+	Round    string    `json:"round,omitempty"`
+	Score    int       `json:"score"`
+	Existing *int      `json:"existing,omitempty"` // the score already saved, on conflict
+	Error    string    `json:"error,omitempty"`
+	Replayed bool      `json:"replayed,omitempty"` // seen before; this is the original result
+	At       time.Time `json:"at"`
+}
+
+// SyncLog remembers the results of offline entries by their client ID, so
+// a sync that is retried (e.g. the response got lost) doesn't apply twice.
+type SyncLog struct {
+	mu       sync.Mutex
+	filename string
+	results  map[string]SyncResult
+
+	// TTL is how long results are kept; set it before serving requests.
+	TTL time.Duration
+}
+
+// LoadSyncLog boots the sync log from sync.json in dataDir.
+func LoadSyncLog(dataDir string) *SyncLog {
+	const syncFilename = "sync.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadSyncLogFile(filepath.Join(dataDir, syncFilename))
+}
+
+// LoadSyncLogFile loads the sync log from a JSON file.
+func LoadSyncLogFile(filename string) *SyncLog {
+	l := &SyncLog{filename: filename, results: make(map[string]SyncResult), TTL: DefaultSyncLogTTL}
+	if data, err := os.ReadFile(filename); err == nil {
+		_ = json.Unmarshal(data, &l.results)
+	}
+	return l
+}
+
+// Get returns the recorded result for an entry ID.
+func (l *SyncLog) Get(id string) (SyncResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res, ok := l.results[id]
+	return res, ok
+}
+
+// Record stores results and drops the ones that are too old to matter.
+func (l *SyncLog) Record(results []SyncResult) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cutoff := time.Now().Add(-l.TTL)
+	for id, res := range l.results {
+		if res.At.Before(cutoff) {
+			delete(l.results, id)
+		}
+	}
+	for _, res := range results {
+		l.results[res.ID] = res
+	}
+	return l.save()
+}
+
+// Forget drops the results for ids, taking back a Record whose entries
+// didn't get saved after all.
+func (l *SyncLog) Forget(ids []string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		delete(l.results, id)
+	}
+	return l.save()
+}
+
+func (l *SyncLog) save() error {
+	data, err := json.MarshalIndent(l.results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(l.filename, data, 0644)
+}
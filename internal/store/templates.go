@@ -0,0 +1,153 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTemplateExists = errors.New("a template with that name already exists")
+
+// BoardTemplate is a saved setup for a recurring event format: how many
+// teams, their colors and which games (with scoring rules) to create.
+type BoardTemplate struct {
+	Name      string         `json:"name"`
+	TeamCount int            `json:"team_count"`
+	Colors    []string       `json:"colors,omitempty"`
+	Games     []TemplateGame `json:"games,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// TemplateGame is a game as stored in a template.
+type TemplateGame struct {
+	Name       string `json:"name"`
+	Scoring    string `json:"scoring,omitempty"`
+	RoundLimit int    `json:"round_limit,omitempty"`
+}
+
+// TemplateFromBoard captures a board's format (not its teams or scores).
+func TemplateFromBoard(name string, b *ScoreBoard) BoardTemplate {
+	t := BoardTemplate{Name: name, CreatedAt: time.Now()}
+	seen := make(map[string]bool)
+	for _, team := range b.Teams {
+		if team == nil {
+			continue
+		}
+		t.TeamCount++
+		t.Colors = append(t.Colors, team.TeamColor["color"])
+		for _, g := range team.Games {
+			if g.GameName == "" || seen[g.GameName] {
+				continue
+			}
+			seen[g.GameName] = true
+			t.Games = append(t.Games, TemplateGame{Name: g.GameName, Scoring: g.Scoring, RoundLimit: g.RoundLimit})
+		}
+	}
+	return t
+}
+
+// Apply adds the template's games (with empty rounds) to every team and
+// gives teams the template colors by position.
+func (t BoardTemplate) Apply(b *ScoreBoard) {
+	for i, team := range b.Teams {
+		if i < len(t.Colors) && t.Colors[i] != "" {
+			team.TeamColor = map[string]string{"color": t.Colors[i]}
+		}
+		for _, g := range t.Games {
+			team.Games = append(team.Games, Game{
+				GameName:   g.Name,
+				Rounds:     make(map[string]int),
+				Scoring:    g.Scoring,
+				RoundLimit: g.RoundLimit,
+			})
+		}
+	}
+}
+
+// Templates is the saved board templates, persisted as JSON.
+type Templates struct {
+	mu        sync.RWMutex
+	filename  string
+	templates []BoardTemplate
+}
+
+// LoadTemplates boots the template store from templates.json in dataDir.
+func LoadTemplates(dataDir string) *Templates {
+	const templatesFilename = "templates.json"
+
+	_ = os.MkdirAll(dataDir, 0755)
+	return LoadTemplatesFile(filepath.Join(dataDir, templatesFilename))
+}
+
+// LoadTemplatesFile loads the template store from a JSON file.
+func LoadTemplatesFile(filename string) *Templates {
+	t := &Templates{filename: filename}
+	if data, err := os.ReadFile(filename); err == nil {
+		_ = json.Unmarshal(data, &t.templates)
+	}
+	return t
+}
+
+// List returns the templates sorted by name.
+func (t *Templates) List() []BoardTemplate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	list := append([]BoardTemplate(nil), t.templates...)
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list
+}
+
+// Get returns the template with the given name.
+func (t *Templates) Get(name string) (BoardTemplate, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, tpl := range t.templates {
+		if strings.EqualFold(tpl.Name, name) {
+			return tpl, true
+		}
+	}
+	return BoardTemplate{}, false
+}
+
+// Add saves a new template.
+func (t *Templates) Add(tpl BoardTemplate) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, existing := range t.templates {
+		if strings.EqualFold(existing.Name, tpl.Name) {
+			return ErrTemplateExists
+		}
+	}
+	t.templates = append(t.templates, tpl)
+	return t.save()
+}
+
+// Remove deletes a template by name.
+func (t *Templates) Remove(name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	kept := t.templates[:0]
+	for _, tpl := range t.templates {
+		if !strings.EqualFold(tpl.Name, name) {
+			kept = append(kept, tpl)
+		}
+	}
+	t.templates = kept
+	return t.save()
+}
+
+// save writes the store to disk. Callers must hold the write lock.
+func (t *Templates) save() error {
+	data, err := json.MarshalIndent(t.templates, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(t.filename, data, 0644)
+}
@@ -0,0 +1,166 @@
+package store
+
+import "time"
+
+// DefaultTimerDuration is used for games that never had a duration set.
+const DefaultTimerDuration = 10 * time.Minute
+
+// Timer is a per-game countdown. Times are kept as wall-clock instants so a
+// running timer keeps counting across restarts, and the server's clock is
+// the one every screen follows.
+type Timer struct {
+	Game       string    `json:"game"`
+	DurationMS int64     `json:"duration_ms"`
+	ElapsedMS  int64     `json:"elapsed_ms"`
+	Running    bool      `json:"running"`
+	StartedAt  time.Time `json:"started_at,omitzero"`
+}
+
+// TimerState is what clients get: enough to draw the countdown locally
+// without drifting from the server.
+type TimerState struct {
+	Game        string `json:"game"`
+	DurationMS  int64  `json:"duration_ms"`
+	RemainingMS int64  `json:"remaining_ms"`
+	Running     bool   `json:"running"`
+	EndsAt      int64  `json:"ends_at,omitempty"` // unix ms, only while running
+}
+
+// Duration returns the timer's configured length.
+func (t *Timer) Duration() time.Duration {
+	return time.Duration(t.DurationMS) * time.Millisecond
+}
+
+// elapsed returns how much of the duration has been used up by now.
+func (t *Timer) elapsed(now time.Time) time.Duration {
+	e := time.Duration(t.ElapsedMS) * time.Millisecond
+	if t.Running {
+		e += now.Sub(t.StartedAt)
+	}
+	return e
+}
+
+// Remaining returns the time left, never below zero.
+func (t *Timer) Remaining(now time.Time) time.Duration {
+	return max(t.Duration()-t.elapsed(now), 0)
+}
+
+// Expired reports whether the countdown has reached zero.
+func (t *Timer) Expired(now time.Time) bool {
+	return t.Remaining(now) == 0
+}
+
+// Idle reports whether the timer is sitting untouched at its full duration.
+func (t *Timer) Idle() bool {
+	return !t.Running && t.ElapsedMS == 0
+}
+
+// Start runs the timer from its full duration.
+func (t *Timer) Start(now time.Time) {
+	t.ElapsedMS = 0
+	t.Running = true
+	t.StartedAt = now
+}
+
+// Pause freezes the timer, banking the time used so far.
+func (t *Timer) Pause(now time.Time) {
+	if !t.Running {
+		return
+	}
+	t.ElapsedMS = min(t.elapsed(now), t.Duration()).Milliseconds()
+	t.Running = false
+	t.StartedAt = time.Time{}
+}
+
+// Resume continues a paused timer.
+func (t *Timer) Resume(now time.Time) {
+	if t.Running || t.Expired(now) {
+		return
+	}
+	t.Running = true
+	t.StartedAt = now
+}
+
+// Reset stops the timer and puts it back to its full duration.
+func (t *Timer) Reset() {
+	t.ElapsedMS = 0
+	t.Running = false
+	t.StartedAt = time.Time{}
+}
+
+// SetDuration changes the length; the timer is reset so it starts clean.
+func (t *Timer) SetDuration(d time.Duration) {
+	t.DurationMS = d.Milliseconds()
+	t.Reset()
+}
+
+// State snapshots the timer for clients.
+func (t *Timer) State(now time.Time) TimerState {
+	s := TimerState{
+		Game:        t.Game,
+		DurationMS:  t.DurationMS,
+		RemainingMS: t.Remaining(now).Milliseconds(),
+		Running:     t.Running && !t.Expired(now),
+	}
+	if s.Running {
+		s.EndsAt = now.Add(t.Remaining(now)).UnixMilli()
+	}
+	return s
+}
+
+// Timer returns the timer for a game, or nil if it has none.
+func (b *ScoreBoard) Timer(game string) *Timer {
+	for _, t := range b.Timers {
+		if t != nil && t.Game == game {
+			return t
+		}
+	}
+	return nil
+}
+
+// EnsureTimer returns the timer for a game, creating one with the default
+// duration if needed.
+func (b *ScoreBoard) EnsureTimer(game string) *Timer {
+	if t := b.Timer(game); t != nil {
+		return t
+	}
+	t := &Timer{Game: game, DurationMS: DefaultTimerDuration.Milliseconds()}
+	b.Timers = append(b.Timers, t)
+	return t
+}
+
+// RenameTimer moves a game's timer to its new name.
+func (b *ScoreBoard) RenameTimer(oldName, newName string) {
+	if t := b.Timer(oldName); t != nil {
+		t.Game = newName
+	}
+}
+
+// RemoveTimer drops a game's timer.
+func (b *ScoreBoard) RemoveTimer(game string) {
+	kept := make([]*Timer, 0, len(b.Timers))
+	for _, t := range b.Timers {
+		if t != nil && t.Game != game {
+			kept = append(kept, t)
+		}
+	}
+	b.Timers = kept
+}
+
+// TimerSnapshot is every timer's state plus the server clock, so clients
+// can correct for their own clock being off.
+type TimerSnapshot struct {
+	ServerNow int64        `json:"server_now"` // unix ms
+	Timers    []TimerState `json:"timers"`
+}
+
+// TimerSnapshot snapshots every timer for clients.
+func (b *ScoreBoard) TimerSnapshot(now time.Time) TimerSnapshot {
+	states := make([]TimerState, 0, len(b.Timers))
+	for _, t := range b.Timers {
+		if t != nil {
+			states = append(states, t.State(now))
+		}
+	}
+	return TimerSnapshot{ServerNow: now.UnixMilli(), Timers: states}
+}
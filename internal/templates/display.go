@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Display panels that can be rotated on the kiosk screen.
+const (
+	PanelStandings = "standings"
+	PanelGames     = "games"
+)
+
+// DisplayOptions configure the /display kiosk view. They come from the query
+// string so a TV can be set up with a bookmark, e.g.
+// /display?views=standings,games&interval=20&ticker=1
+type DisplayOptions struct {
+	Panels   []string // panels to rotate through, in order
+	Interval int      // seconds each panel stays up
+	Ticker   bool     // scroll standings and game leaders along the bottom
+	Title    bool     // show the board name
+	Query    string   // raw query, reused when refreshing the panels
+}
+
+// Rotates reports whether there is more than one panel to cycle through.
+func (o DisplayOptions) Rotates() bool {
+	return len(o.Panels) > 1
+}
+
+// Shows reports whether the given panel is enabled.
+func (o DisplayOptions) Shows(panel string) bool {
+	for _, p := range o.Panels {
+		if p == panel {
+			return true
+		}
+	}
+	return false
+}
+
+// IntervalMillis is the rotation interval for the client script.
+func (o DisplayOptions) IntervalMillis() string {
+	return strconv.Itoa(o.Interval * 1000)
+}
+
+// DisplayGrid picks columns and rows for n team cards so the cards end up
+// as large as possible on a 16:9 screen.
+func DisplayGrid(n int) (cols, rows int) {
+	if n <= 1 {
+		return 1, 1
+	}
+	best := 0.0
+	for c := 1; c <= n; c++ {
+		r := (n + c - 1) / c
+		// Cell size in screen units, compared against a 4:3 card.
+		scale := math.Min(16.0/float64(c)/4, 9.0/float64(r)/3)
+		if scale > best {
+			best, cols, rows = scale, c, r
+		}
+	}
+	return cols, rows
+}
+
+// DisplayGridStyle is the CSS grid template for n team cards.
+func DisplayGridStyle(n int) string {
+	cols, rows := DisplayGrid(n)
+	return "display:grid;grid-template-columns:repeat(" + strconv.Itoa(cols) + ",minmax(0,1fr));" +
+		"grid-template-rows:repeat(" + strconv.Itoa(rows) + ",minmax(0,1fr));"
+}
+
+// DisplayFontSize scales text to the card size: pct is the share of a card's
+// height the text should take.
+func DisplayFontSize(n int, pct int) string {
+	cols, rows := DisplayGrid(n)
+	// Cards share roughly 80vh (title and ticker take the rest) and 96vw.
+	h := 80 * pct / 100 / rows
+	w := 96 * pct / 100 / cols
+	return "font-size:min(" + strconv.Itoa(h) + "vh," + strconv.Itoa(w) + "vw);"
+}
+
+// GamesFontSize scales the per-game table so every row fits on screen.
+func GamesFontSize(games int) string {
+	if games < 4 {
+		games = 4
+	}
+	return "font-size:" + strconv.Itoa(60/(games+1)) + "vh;"
+}
+
+// TickerText joins the ticker items with separators.
+func TickerText(items []string) string {
+	return strings.Join(items, "   •   ")
+}
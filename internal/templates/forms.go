@@ -0,0 +1,270 @@
+package templates
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrjxtr-dev/score-board/internal/store"
+	"github.com/mrjxtr-dev/score-board/internal/validate"
+)
+
+// MaxTeams is how many team slots the create/settings forms show.
+const MaxTeams = 4
+
+// TeamForm is one team slot as typed into the form.
+type TeamForm struct {
+	Name    string
+	Members string
+}
+
+// BoardForm holds the create/settings form input so it can be shown again
+// with field errors after a failed submit.
+type BoardForm struct {
+	BoardName string
+	Teams     [MaxTeams]TeamForm
+	Template  string
+	Errors    validate.Errors
+}
+
+// BoardFormFrom fills the form from the saved board.
+func BoardFormFrom(b *store.ScoreBoard) BoardForm {
+	f := BoardForm{}
+	if b == nil {
+		return f
+	}
+	f.BoardName = b.BoardName
+	for i := range f.Teams {
+		f.Teams[i] = TeamForm{
+			Name:    TeamNameAt(b, i),
+			Members: TeamMembersCSVAt(b, i),
+		}
+	}
+	return f
+}
+
+// Err returns the error for a field, if any.
+func (f BoardForm) Err(field string) string {
+	return f.Errors.Get(field)
+}
+
+// TeamField returns the form field name for a 1-based team slot.
+func TeamField(prefix string, i int) string {
+	return prefix + strconv.Itoa(i)
+}
+
+// GamesForm holds the add/rename/schedule game input after a failed submit.
+type GamesForm struct {
+	GameName  string
+	RenameOld string
+	RenameNew string
+
+	ScheduleGame string
+	Start        string
+	End          string
+	Location     string
+
+	SettingsGame string
+	RoundLimit   string
+
+	Errors validate.Errors
+}
+
+// DateTimeLocal is the layout used by <input type="datetime-local">.
+const DateTimeLocal = "2006-01-02T15:04"
+
+// Err returns the error for a field, if any.
+func (f GamesForm) Err(field string) string {
+	return f.Errors.Get(field)
+}
+
+// RenameErr returns the rename error for the given game, if any.
+func (f GamesForm) RenameErr(name string) string {
+	if f.RenameOld != name {
+		return ""
+	}
+	return f.Errors.Get("new_name")
+}
+
+// RenameValue is what to show in a game's rename box.
+func (f GamesForm) RenameValue(name string) string {
+	if f.RenameOld == name && f.Errors.Has("new_name") {
+		return f.RenameNew
+	}
+	return name
+}
+
+// ScheduleErr returns the error for a schedule field of the given game.
+func (f GamesForm) ScheduleErr(game, field string) string {
+	if f.ScheduleGame != game {
+		return ""
+	}
+	return f.Errors.Get(field)
+}
+
+// ScheduleValue is what to show in a game's schedule box: the rejected
+// input after a failed submit, otherwise the saved value.
+func (f GamesForm) ScheduleValue(game, field string, info *store.GameInfo) string {
+	if f.ScheduleGame == game && f.Errors.Any() {
+		switch field {
+		case "start":
+			return f.Start
+		case "end":
+			return f.End
+		case "location":
+			return f.Location
+		}
+	}
+	if info == nil {
+		return ""
+	}
+	switch field {
+	case "start":
+		if !info.Start.IsZero() {
+			return info.Start.Local().Format(DateTimeLocal)
+		}
+	case "end":
+		if !info.End.IsZero() {
+			return info.End.Local().Format(DateTimeLocal)
+		}
+	case "location":
+		return info.Location
+	}
+	return ""
+}
+
+// SettingsErr returns the error for a scoring field of the given game.
+func (f GamesForm) SettingsErr(game, field string) string {
+	if f.SettingsGame != game {
+		return ""
+	}
+	return f.Errors.Get(field)
+}
+
+// RoundLimitValue is what to show in a game's round limit box.
+func (f GamesForm) RoundLimitValue(game string, g store.Game) string {
+	if f.SettingsGame == game && f.Errors.Any() {
+		return f.RoundLimit
+	}
+	if g.RoundLimit == 0 {
+		return ""
+	}
+	return strconv.Itoa(g.RoundLimit)
+}
+
+// ScoreForm holds a team's score input for one game after a failed submit.
+type ScoreForm struct {
+	GameName string
+	Score    string
+	Errors   validate.Errors
+}
+
+// ErrFor returns the error for a field of the given game's forms, if any.
+func (f ScoreForm) ErrFor(game, field string) string {
+	if f.GameName != game {
+		return ""
+	}
+	return f.Errors.Get(field)
+}
+
+// ScoreFor is what to show in the given game's score box.
+func (f ScoreForm) ScoreFor(game string) string {
+	if f.GameName != game {
+		return ""
+	}
+	return f.Score
+}
+
+// ErrBorder turns an input's border red when it has an error.
+func ErrBorder(msg string) string {
+	if msg == "" {
+		return ""
+	}
+	return "border-color:#f87171;"
+}
+
+// TimerForm holds a game clock's duration input after a failed submit.
+type TimerForm struct {
+	GameName string
+	Minutes  string
+	Errors   validate.Errors
+}
+
+// ErrFor returns the error for the given game's duration box, if any.
+func (f TimerForm) ErrFor(game string) string {
+	if f.GameName != game {
+		return ""
+	}
+	return f.Errors.Get("minutes")
+}
+
+// MinutesFor is what to show in the given game's duration box.
+func (f TimerForm) MinutesFor(game string, t *store.Timer) string {
+	if f.GameName == game && f.Errors.Any() {
+		return f.Minutes
+	}
+	if t == nil {
+		return strconv.Itoa(int(store.DefaultTimerDuration.Minutes()))
+	}
+	return strconv.Itoa(int(t.Duration().Minutes()))
+}
+
+// SeasonForm holds the season create/edit input after a failed submit.
+type SeasonForm struct {
+	Name    string
+	Points  string
+	Aliases string
+	Boards  []string
+	Errors  validate.Errors
+}
+
+// SeasonFormFrom fills the form from a saved season.
+func SeasonFormFrom(s store.Season) SeasonForm {
+	points := make([]string, len(s.Points))
+	for i, p := range s.Points {
+		points[i] = strconv.Itoa(p)
+	}
+	aliases := make([]string, 0, len(s.Aliases))
+	for alias, team := range s.Aliases {
+		aliases = append(aliases, alias+" = "+team)
+	}
+	sort.Strings(aliases)
+	return SeasonForm{
+		Name:    s.Name,
+		Points:  strings.Join(points, ", "),
+		Aliases: strings.Join(aliases, "\n"),
+		Boards:  s.Boards,
+	}
+}
+
+// Err returns the error for a field, if any.
+func (f SeasonForm) Err(field string) string {
+	return f.Errors.Get(field)
+}
+
+// HasBoard reports whether the archived board is ticked.
+func (f SeasonForm) HasBoard(id string) bool {
+	return slices.Contains(f.Boards, id)
+}
+
+// SheetForm holds a transcribed scoresheet after a failed submit.
+type SheetForm struct {
+	Values map[string]string
+	Errors validate.Errors
+}
+
+// SheetField returns the form field name for a team (by board index) and round.
+func SheetField(team int, round string) string {
+	return "score_" + strconv.Itoa(team) + "_" + round
+}
+
+// Value is what to show in a scoresheet entry box.
+func (f SheetForm) Value(team int, round string) string {
+	return f.Values[SheetField(team, round)]
+}
+
+// Err returns the error for a scoresheet entry box, if any.
+func (f SheetForm) Err(team int, round string) string {
+	return f.Errors.Get(SheetField(team, round))
+}
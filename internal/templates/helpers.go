@@ -3,25 +3,22 @@ package templates
 import (
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mrjxtr-dev/score-board/internal/auth"
 	"github.com/mrjxtr-dev/score-board/internal/store"
 )
 
+// DefaultColors are the team colors handed out in slot order, set from the
+// config at startup. Slots past the end get white.
+var DefaultColors []string
+
 // DefaultColorHex returns the default team color (hex) for a 1-based index.
-// Keeps it simple: pink, red, blue, yellow in that order.
 func DefaultColorHex(i int) string {
-	switch i {
-	case 1:
-		return "#D50059" // pink
-	case 2:
-		return "#C50000" // red
-	case 3:
-		return "#1D03AF" // blue
-	case 4:
-		return "#FFBB02" // yellow
-	default:
+	if i < 1 || i > len(DefaultColors) {
 		return "#FFFFFF"
 	}
+	return DefaultColors[i-1]
 }
 
 // TeamNameAt returns the team name at zero-based index, or empty if out of range.
@@ -81,3 +78,266 @@ func NextRoundForGame(g store.Game) int {
 	}
 	return maxRound + 1
 }
+
+// TeamLink pairs an issued team token with the link handed to the captain.
+type TeamLink struct {
+	Token auth.TeamToken
+	URL   string
+}
+
+// ConflictPage explains why a form from an older version of the board was
+// turned away.
+type ConflictPage struct {
+	Back    string   // where to go to try again
+	Since   int      // how many edits happened in between
+	Changes []string // what changed, when the old version is still known
+	Known   bool
+}
+
+// RankedTeam is a team with its place in the standings.
+type RankedTeam struct {
+	Rank int
+	*store.Team
+}
+
+// RankedTeams returns the board's teams in store standings order, so every
+// page ranks and breaks ties the same way.
+func RankedTeams(b *store.ScoreBoard) []RankedTeam {
+	standings := b.Standings()
+	if len(standings) == 0 {
+		return nil
+	}
+	byName := make(map[string]*store.Team, len(b.Teams))
+	for _, t := range b.Teams {
+		if t != nil {
+			byName[t.TeamName] = t
+		}
+	}
+	teams := make([]RankedTeam, 0, len(standings))
+	for _, s := range standings {
+		teams = append(teams, RankedTeam{Rank: s.Rank, Team: byName[s.Team]})
+	}
+	return teams
+}
+
+// GameTotal returns a team's score for one game.
+func GameTotal(t *store.Team, game string) int {
+	if t == nil {
+		return 0
+	}
+	total := 0
+	for _, g := range t.Games {
+		if g.GameName == game {
+			total += g.Score()
+		}
+	}
+	return total
+}
+
+// GameLeader returns the team with the best total in a game, or nil if
+// nobody has scored or the top spot is tied.
+func GameLeader(b *store.ScoreBoard, game string) *store.Team {
+	if b == nil {
+		return nil
+	}
+	var leader *store.Team
+	best, tied, scored := 0, false, false
+	for _, t := range b.Teams {
+		if t == nil || !hasRounds(t, game) {
+			continue
+		}
+		v := GameTotal(t, game)
+		switch {
+		case !scored || v > best:
+			leader, best, tied, scored = t, v, false, true
+		case v == best:
+			tied = true
+		}
+	}
+	if tied {
+		return nil
+	}
+	return leader
+}
+
+func hasRounds(t *store.Team, game string) bool {
+	for _, g := range t.Games {
+		if g.GameName == game && len(g.Rounds) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TickerItems builds the short lines scrolled along the bottom of the display.
+func TickerItems(b *store.ScoreBoard) []string {
+	standings := RankedTeams(b)
+	if len(standings) == 0 {
+		return nil
+	}
+	items := make([]string, 0, len(standings)+8)
+	lead := standings[0]
+	if len(standings) > 1 {
+		gap := lead.TotalScore() - standings[1].TotalScore()
+		if gap == 0 {
+			items = append(items, "Tied at the top: "+lead.TeamName+" and "+standings[1].TeamName)
+		} else {
+			items = append(items, lead.TeamName+" leads by "+strconv.Itoa(gap))
+		}
+	}
+	for _, t := range standings {
+		items = append(items, "#"+strconv.Itoa(t.Rank)+" "+t.TeamName+" "+strconv.Itoa(t.TotalScore()))
+	}
+	for _, g := range UniqueGameNames(b) {
+		if l := GameLeader(b, g); l != nil {
+			items = append(items, g+": "+l.TeamName+" on top with "+strconv.Itoa(GameTotal(l, g)))
+		}
+	}
+	return items
+}
+
+// FormatClock renders a countdown as m:ss (or h:mm:ss for long games).
+func FormatClock(d time.Duration) string {
+	secs := int((d + time.Second - 1) / time.Second)
+	h, m, s := secs/3600, secs/60%60, secs%60
+	pad := func(v int) string {
+		if v < 10 {
+			return "0" + strconv.Itoa(v)
+		}
+		return strconv.Itoa(v)
+	}
+	if h > 0 {
+		return strconv.Itoa(h) + ":" + pad(m) + ":" + pad(s)
+	}
+	return strconv.Itoa(m) + ":" + pad(s)
+}
+
+// TimerStatus is a short label for a timer's state.
+func TimerStatus(t *store.Timer, now time.Time) string {
+	switch {
+	case t == nil || t.Idle():
+		return "ready"
+	case t.Expired(now):
+		return "time"
+	case t.Running:
+		return "running"
+	default:
+		return "paused"
+	}
+}
+
+// ActiveTimers returns the timers worth showing to the audience: anything
+// that has been started and not reset.
+func ActiveTimers(b *store.ScoreBoard) []*store.Timer {
+	if b == nil {
+		return nil
+	}
+	var list []*store.Timer
+	for _, t := range b.Timers {
+		if t != nil && !t.Idle() {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// GameSettings returns the first team's copy of a game, which carries the
+// scoring mode and round limit shared by all teams.
+func GameSettings(b *store.ScoreBoard, name string) store.Game {
+	if b == nil {
+		return store.Game{}
+	}
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		for _, g := range t.Games {
+			if g.GameName == name {
+				return g
+			}
+		}
+	}
+	return store.Game{}
+}
+
+// ScoringLabel describes how a game is scored, e.g. "Best round, 3 rounds max".
+func ScoringLabel(g store.Game) string {
+	label := "Sum of rounds"
+	switch g.Scoring {
+	case store.ScoringBest:
+		label = "Best round"
+	case store.ScoringAverage:
+		label = "Average of rounds"
+	}
+	if g.RoundLimit > 0 {
+		label += ", " + strconv.Itoa(g.RoundLimit) + " rounds max"
+	}
+	return label
+}
+
+// TemplateSummary is a one-line description of a board template.
+func TemplateSummary(t store.BoardTemplate) string {
+	games := make([]string, 0, len(t.Games))
+	for _, g := range t.Games {
+		games = append(games, g.Name)
+	}
+	summary := strconv.Itoa(t.TeamCount) + " teams"
+	if len(games) > 0 {
+		summary += ": " + strings.Join(games, ", ")
+	}
+	return summary
+}
+
+// PointsLabel describes a season's points table, e.g. "Points: 1st 10 · 2nd 6".
+func PointsLabel(points []int) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = ordinal(i+1) + " " + strconv.Itoa(p)
+	}
+	return "Points: " + strings.Join(parts, " · ")
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// Shortcut is the key that picks a team on the rapid entry screen.
+type Shortcut struct {
+	Key  string
+	Team *store.Team
+}
+
+// TeamShortcuts gives every team a one-letter key: the first letter of its
+// name that isn't taken yet, or else the first free letter of the alphabet.
+func TeamShortcuts(b *store.ScoreBoard) []Shortcut {
+	taken := make(map[rune]bool)
+	var out []Shortcut
+	for _, t := range b.Teams {
+		if t == nil {
+			continue
+		}
+		key := rune(0)
+		for _, c := range strings.ToLower(t.TeamName) + "abcdefghijklmnopqrstuvwxyz" {
+			if c >= 'a' && c <= 'z' && !taken[c] {
+				key = c
+				break
+			}
+		}
+		if key == 0 {
+			continue // more than 26 teams; the form only has a handful
+		}
+		taken[key] = true
+		out = append(out, Shortcut{Key: string(key), Team: t})
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package templates
+
+import (
+	"strconv"
+
+	"github.com/mrjxtr-dev/score-board/internal/stats"
+)
+
+// Chart sizes for the stats page, in SVG user units.
+const (
+	chartWidth    = 600
+	chartLabelW   = 150
+	chartRowH     = 34
+	histWidth     = 280
+	histHeight    = 120
+	histLabelRowH = 18
+)
+
+// Bar is one bar of a server-rendered SVG chart, with its coordinates
+// already worked out.
+type Bar struct {
+	Label string
+	Value string
+	Color string
+	X, Y  string
+	W, H  string
+	// TextX/TextY place the value next to (or above) the bar.
+	TextX, TextY string
+}
+
+// BarChart is a horizontal bar chart, one row per team.
+type BarChart struct {
+	Bars   []Bar
+	Height string
+}
+
+// TeamBars builds a horizontal bar chart from one number per team.
+// Negative values are drawn as empty bars.
+func TeamBars(teams []stats.TeamStats, value func(stats.TeamStats) float64) BarChart {
+	top := 0.0
+	for _, t := range teams {
+		top = max(top, value(t))
+	}
+	chart := BarChart{Height: strconv.Itoa(len(teams)*chartRowH + 4)}
+	for i, t := range teams {
+		v := value(t)
+		w := 0.0
+		if top > 0 && v > 0 {
+			w = v / top * float64(chartWidth-chartLabelW-60)
+		}
+		y := float64(i*chartRowH + 4)
+		chart.Bars = append(chart.Bars, Bar{
+			Label: t.Team,
+			Value: strconv.FormatFloat(v, 'f', -1, 64),
+			Color: t.Color,
+			X:     num(chartLabelW),
+			Y:     num(y + 5),
+			W:     num(w),
+			H:     num(chartRowH - 10),
+			TextX: num(chartLabelW + w + 8),
+			TextY: num(y + chartRowH/2),
+		})
+	}
+	return chart
+}
+
+// Histogram builds the vertical bars of a team's score distribution.
+func Histogram(t stats.TeamStats, buckets []stats.Bucket) []Bar {
+	top := 0
+	for _, c := range t.Distribution {
+		top = max(top, c)
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+	slot := float64(histWidth) / float64(len(buckets))
+	plot := float64(histHeight - histLabelRowH*2)
+	bars := make([]Bar, 0, len(buckets))
+	for i, b := range buckets {
+		c := t.Distribution[i]
+		h := 0.0
+		if top > 0 {
+			h = float64(c) / float64(top) * plot
+		}
+		x := float64(i) * slot
+		y := float64(histLabelRowH) + plot - h
+		bars = append(bars, Bar{
+			Label: BucketLabel(b),
+			Value: strconv.Itoa(c),
+			Color: t.Color,
+			X:     num(x + 3),
+			Y:     num(y),
+			W:     num(slot - 6),
+			H:     num(h),
+			TextX: num(x + slot/2),
+			TextY: num(y - 4),
+		})
+	}
+	return bars
+}
+
+// BucketLabel is the short range text under a histogram bar.
+func BucketLabel(b stats.Bucket) string {
+	if b.Min == b.Max {
+		return strconv.Itoa(b.Min)
+	}
+	return strconv.Itoa(b.Min) + "–" + strconv.Itoa(b.Max)
+}
+
+// RoundLabel describes a high or low round, e.g. "12 (Darts R3)".
+func RoundLabel(rs *stats.RoundScore) string {
+	if rs == nil {
+		return "–"
+	}
+	return strconv.Itoa(rs.Score) + " (" + rs.Game + " R" + rs.Round + ")"
+}
+
+// FormatAverage shows an average with at most one decimal.
+func FormatAverage(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', 1, 64)
+}
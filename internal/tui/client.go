@@ -0,0 +1,277 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mrjxtr-dev/score-board/internal/csrf"
+	"github.com/mrjxtr-dev/score-board/internal/idempotency"
+)
+
+// errSignedOut means the server sent us to the login page: the session
+// expired or the server restarted.
+var errSignedOut = errors.New("signed out; run tui again to sign back in")
+
+// standings mirrors /board/standings.json.
+type standings struct {
+	Board    string   `json:"board"`
+	Version  int      `json:"version"`
+	Finished bool     `json:"finished"`
+	Games    []string `json:"games"`
+	Teams    []struct {
+		Rank  int            `json:"rank"`
+		Team  string         `json:"team"`
+		Color string         `json:"color"`
+		Key   string         `json:"key"`
+		Total int            `json:"total"`
+		Games map[string]int `json:"games"`
+	} `json:"teams"`
+}
+
+// entry is one score for /rapid/batch.
+type entry struct {
+	Team  string `json:"team"`
+	Game  string `json:"game"`
+	Round string `json:"round,omitempty"`
+	Score int    `json:"score"`
+	Undo  bool   `json:"undo,omitempty"`
+}
+
+// client talks to a running server the way a browser would: a cookie jar
+// for the session and CSRF cookies, and no redirects followed.
+type client struct {
+	base *url.URL
+	http *http.Client
+	// stream has no timeout, for the event stream
+	stream *http.Client
+}
+
+// newClient makes a client for the server at raw. If caFile is set, the
+// certificates in it are trusted on top of the system ones, for servers
+// using the local CA.
+func newClient(raw, caFile string) (*client, error) {
+	base, err := url.Parse(strings.TrimRight(raw, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q", raw)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	jar, _ := cookiejar.New(nil)
+	noRedirect := func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &client{
+		base:   base,
+		http:   &http.Client{Jar: jar, Timeout: 10 * time.Second, CheckRedirect: noRedirect, Transport: transport},
+		stream: &http.Client{Jar: jar, CheckRedirect: noRedirect, Transport: transport},
+	}, nil
+}
+
+func (c *client) url(path string) string {
+	return c.base.String() + path
+}
+
+// token is the CSRF token the server gave us.
+func (c *client) token() string {
+	for _, ck := range c.http.Jar.Cookies(c.base) {
+		if ck.Name == csrf.CookieName {
+			return ck.Value
+		}
+	}
+	return ""
+}
+
+// login signs in like the login form does.
+func (c *client) login(ctx context.Context, user, password string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url("/login"), nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	form := url.Values{
+		"username":     {user},
+		"password":     {password},
+		csrf.FieldName: {c.token()},
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.url("/login"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err = c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusSeeOther:
+		return nil
+	case http.StatusUnauthorized:
+		return errors.New("wrong username or password")
+	default:
+		return fmt.Errorf("login failed: %s", res.Status)
+	}
+}
+
+// standings fetches the current table.
+func (c *client) standings(ctx context.Context) (standings, error) {
+	var s standings
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url("/board/standings.json"), nil)
+	if err != nil {
+		return s, err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return s, err
+	}
+	defer res.Body.Close()
+	if err := statusError(res); err != nil {
+		return s, err
+	}
+	return s, json.NewDecoder(res.Body).Decode(&s)
+}
+
+// send posts entries as one batch. A rejected batch comes back as the
+// server's first error message.
+func (c *client) send(ctx context.Context, entries ...entry) ([]entry, error) {
+	body, err := json.Marshal(map[string][]entry{"entries": entries})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url("/rapid/batch"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(csrf.HeaderName, c.token())
+	req.Header.Set(idempotency.HeaderName, idempotency.NewKey())
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Results []entry `json:"results"`
+		Errors  []struct {
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	if res.StatusCode == http.StatusBadRequest && strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(res.Body).Decode(&out); err == nil && len(out.Errors) > 0 {
+			return nil, errors.New(out.Errors[0].Error)
+		}
+	}
+	if err := statusError(res); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out.Results, nil
+}
+
+// statusError turns anything but a 200 into an error, using the plain
+// text body http.Error writes.
+func statusError(res *http.Response) error {
+	switch {
+	case res.StatusCode == http.StatusOK:
+		return nil
+	case res.StatusCode == http.StatusSeeOther && strings.HasPrefix(res.Header.Get("Location"), "/login"):
+		return errSignedOut
+	case res.StatusCode == http.StatusForbidden:
+		return errors.New("not allowed; sign in as a scorekeeper or admin to enter scores")
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	if text := strings.TrimSpace(string(msg)); text != "" && !strings.HasPrefix(text, "<") {
+		return errors.New(text)
+	}
+	return errors.New(res.Status)
+}
+
+// events follows the live update stream until ctx is done, reconnecting
+// when it drops. changed is called for every board event and connected
+// whenever the connection comes up or goes down.
+func (c *client) events(ctx context.Context, changed func(), connected func(bool)) {
+	wait := time.Second
+	for ctx.Err() == nil {
+		up := func(ok bool) {
+			if ok {
+				wait = time.Second
+			}
+			connected(ok)
+		}
+		_ = c.follow(ctx, changed, up)
+		connected(false)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, 15*time.Second)
+	}
+}
+
+func (c *client) follow(ctx context.Context, changed func(), connected func(bool)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url("/events"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	res, err := c.stream.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.New(res.Status)
+	}
+	connected(true)
+	// Catch up on whatever happened while we were away
+	changed()
+
+	name := ""
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case line == "":
+			if name == "board" {
+				changed()
+			}
+			name = ""
+		}
+	}
+	return sc.Err()
+}
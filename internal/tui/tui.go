@@ -0,0 +1,481 @@
+// Package tui is a terminal client for a running score-board server: it
+// shows the standings in team colors, redraws them as the live stream
+// reports changes, and takes scores typed the same way as the rapid entry
+// screen ("r 15" then Enter).
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Options says where the server is and who to sign in as.
+type Options struct {
+	URL      string
+	User     string
+	Password string
+	// CAFile is an extra CA certificate to trust, for a server using the
+	// local CA.
+	CAFile string
+}
+
+// entryPattern is a team key and a score, as on the rapid entry screen.
+var entryPattern = regexp.MustCompile(`^\s*([a-zA-Z])\s*(-?\d+)\s*$`)
+
+// maxInput keeps the entry line short; nothing valid is longer.
+const maxInput = 24
+
+// result is what came back for a sent entry.
+type result struct {
+	e   entry
+	err error
+}
+
+// model is everything the screen shows. Only the Run loop touches it.
+type model struct {
+	st     standings
+	game   int
+	input  []rune
+	live   bool
+	status string
+	failed bool
+	// saved are the entries made here, newest last, for Ctrl+Z
+	saved []entry
+}
+
+// Run signs in, then draws the board until the user quits.
+func Run(ctx context.Context, opts Options) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("tui needs an interactive terminal")
+	}
+	c, err := newClient(opts.URL, opts.CAFile)
+	if err != nil {
+		return err
+	}
+	if err := c.login(ctx, opts.User, opts.Password); err != nil {
+		return err
+	}
+	st, err := c.standings(ctx)
+	if err != nil {
+		return err
+	}
+
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	out := os.Stdout
+	// Alternate screen, hidden cursor; put both back on the way out
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+		_ = term.Restore(fd, old)
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan []byte)
+	go readKeys(os.Stdin, keys)
+	changes := make(chan struct{}, 1)
+	liveState := make(chan bool, 4)
+	go c.events(ctx, func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}, func(ok bool) {
+		select {
+		case liveState <- ok:
+		case <-ctx.Done():
+		}
+	})
+
+	fresh := make(chan standings)
+	fetchErr := make(chan error)
+	results := make(chan result)
+	fetching, again := false, false
+	fetch := func() {
+		if fetching {
+			again = true
+			return
+		}
+		fetching = true
+		go func() {
+			st, err := c.standings(ctx)
+			if err != nil {
+				select {
+				case fetchErr <- err:
+				case <-ctx.Done():
+				}
+				return
+			}
+			select {
+			case fresh <- st:
+			case <-ctx.Done():
+			}
+		}()
+	}
+	send := func(e entry) {
+		go func() {
+			got, err := c.send(ctx, e)
+			if err == nil && len(got) > 0 {
+				e = got[0]
+			}
+			select {
+			case results <- result{e, err}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	m := &model{st: st}
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		draw(out, fd, m)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+			// Picks up terminal resizes
+		case ok := <-liveState:
+			m.live = ok
+		case <-changes:
+			fetch()
+		case st := <-fresh:
+			m.setStandings(st)
+			fetching = false
+			if again {
+				again = false
+				fetch()
+			}
+		case err := <-fetchErr:
+			fetching = false
+			m.fail(err)
+			if errors.Is(err, errSignedOut) {
+				return err
+			}
+		case r := <-results:
+			if r.err != nil {
+				m.fail(r.err)
+				if errors.Is(r.err, errSignedOut) {
+					return r.err
+				}
+				continue
+			}
+			if r.e.Undo {
+				m.ok("Undid " + describe(r.e))
+			} else {
+				m.saved = append(m.saved, r.e)
+				m.ok("Saved " + describe(r.e))
+			}
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			if quit := m.key(k, send); quit {
+				return nil
+			}
+		}
+	}
+}
+
+// readKeys passes on raw key presses until stdin closes.
+func readKeys(r io.Reader, keys chan<- []byte) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// key handles one read from the terminal and reports whether to quit.
+func (m *model) key(k []byte, send func(entry)) bool {
+	switch string(k) {
+	case "\x03", "\x04": // Ctrl+C, Ctrl+D
+		return true
+	case "\x1b": // Esc clears the line, or quits when it's empty
+		if len(m.input) == 0 {
+			return true
+		}
+		m.input = nil
+		return false
+	case "\t", "\x1b[C", "\x1bOC":
+		m.moveGame(1)
+		return false
+	case "\x1b[Z", "\x1b[D", "\x1bOD":
+		m.moveGame(-1)
+		return false
+	case "\r", "\n":
+		m.submit(send)
+		return false
+	case "\x7f", "\b":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+		return false
+	case "\x15": // Ctrl+U
+		m.input = nil
+		return false
+	case "\x1a": // Ctrl+Z
+		m.undo(send)
+		return false
+	}
+	if k[0] == 0x1b {
+		return false // some other escape sequence
+	}
+	for _, r := range string(k) {
+		if r == '\r' || r == '\n' {
+			m.submit(send)
+			continue
+		}
+		if r >= ' ' && r != 0x7f && len(m.input) < maxInput {
+			m.input = append(m.input, r)
+		}
+	}
+	return false
+}
+
+func (m *model) moveGame(d int) {
+	if n := len(m.st.Games); n > 0 {
+		m.game = (m.game + d + n) % n
+	}
+}
+
+// submit parses the entry line and sends it.
+func (m *model) submit(send func(entry)) {
+	line := strings.TrimSpace(string(m.input))
+	if line == "" {
+		return
+	}
+	if m.st.Finished {
+		m.fail(errors.New("the event is finished; scores can't change"))
+		return
+	}
+	if len(m.st.Games) == 0 {
+		m.fail(errors.New("no games yet"))
+		return
+	}
+	match := entryPattern.FindStringSubmatch(line)
+	if match == nil {
+		m.fail(errors.New("type a team key and a score, like r 15"))
+		return
+	}
+	team := ""
+	for _, t := range m.st.Teams {
+		if t.Key == strings.ToLower(match[1]) {
+			team = t.Team
+		}
+	}
+	if team == "" {
+		m.fail(fmt.Errorf("no team on key %q", strings.ToLower(match[1])))
+		return
+	}
+	score, err := strconv.Atoi(match[2])
+	if err != nil {
+		m.fail(errors.New("score is too big"))
+		return
+	}
+	e := entry{Team: team, Game: m.st.Games[m.game], Score: score}
+	m.input = nil
+	m.status, m.failed = "Sending "+describe(e)+"…", false
+	send(e)
+}
+
+// undo takes back the newest entry made here.
+func (m *model) undo(send func(entry)) {
+	if len(m.saved) == 0 {
+		m.fail(errors.New("nothing to undo"))
+		return
+	}
+	e := m.saved[len(m.saved)-1]
+	m.saved = m.saved[:len(m.saved)-1]
+	e.Undo = true
+	m.status, m.failed = "Undoing "+describe(e)+"…", false
+	send(e)
+}
+
+// setStandings swaps in a new table, keeping the same game selected.
+func (m *model) setStandings(st standings) {
+	cur := ""
+	if m.game < len(m.st.Games) {
+		cur = m.st.Games[m.game]
+	}
+	m.st, m.game = st, 0
+	for i, g := range st.Games {
+		if g == cur {
+			m.game = i
+		}
+	}
+}
+
+func (m *model) ok(msg string) { m.status, m.failed = msg, false }
+
+func (m *model) fail(err error) { m.status, m.failed = err.Error(), true }
+
+func describe(e entry) string {
+	s := e.Team + " · " + e.Game
+	if e.Round != "" {
+		s += " round " + e.Round
+	}
+	return s + ": " + strconv.Itoa(e.Score)
+}
+
+// draw repaints the whole screen from the top, clearing what's left over
+// from the previous frame.
+func draw(w io.Writer, fd int, m *model) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width < 20 {
+		width, height = 80, 24
+	}
+	var lines []string
+	add := func(s string) { lines = append(lines, s) }
+
+	name := m.st.Board
+	if name == "" {
+		name = "Score Board"
+	}
+	state := "\x1b[31m○ offline\x1b[0m"
+	if m.live {
+		state = "\x1b[32m● live\x1b[0m"
+	}
+	right := time.Now().Format("15:04:05") + "  "
+	gap := width - 2 - utf8.RuneCountInString(name) - utf8.RuneCountInString(right) - 8
+	add(" \x1b[1m" + clip(name, width-20) + "\x1b[0m" + strings.Repeat(" ", max(gap, 1)) + right + state)
+	if m.st.Finished {
+		add(" \x1b[33mEvent finished — read only\x1b[0m")
+	}
+	add("")
+
+	if len(m.st.Teams) == 0 {
+		add(" No teams yet.")
+	} else {
+		nameW := 4
+		for _, t := range m.st.Teams {
+			nameW = max(nameW, utf8.RuneCountInString(t.Team))
+		}
+		nameW = min(nameW, 24)
+		// As many game columns as fit, starting with the selected one
+		fixed := 21 + nameW
+		cols := max((width-fixed)/10, 0)
+		games := shownGames(m.st.Games, m.game, cols)
+
+		head := fmt.Sprintf(" %3s  %2s %3s %s %7s", "#", "", "", pad("TEAM", nameW), "TOTAL")
+		for _, g := range games {
+			label := fmt.Sprintf(" %9s", clip(g, 9))
+			if g == m.st.Games[m.game] {
+				label = "\x1b[4m" + label + "\x1b[24m"
+			}
+			head += label
+		}
+		add("\x1b[2m" + head + "\x1b[0m")
+		for _, t := range m.st.Teams {
+			key := "   "
+			if t.Key != "" {
+				key = "[" + t.Key + "]"
+			}
+			row := fmt.Sprintf(" %3d  %s %s \x1b[1m%s\x1b[0m %7d", t.Rank, swatch(t.Color), key, pad(clip(t.Team, nameW), nameW), t.Total)
+			for _, g := range games {
+				row += fmt.Sprintf(" %9d", t.Games[g])
+			}
+			add(row)
+		}
+	}
+	add("")
+
+	if len(m.st.Games) > 0 {
+		add(" Game: \x1b[1m‹ " + m.st.Games[m.game] + " ›\x1b[0m  \x1b[2mTab/←→ to switch\x1b[0m")
+	} else {
+		add(" \x1b[2mNo games yet\x1b[0m")
+	}
+	add(" > " + string(m.input) + "\x1b[7m \x1b[0m")
+	switch {
+	case m.status == "":
+		add("")
+	case m.failed:
+		add(" \x1b[31m" + clip(m.status, width-2) + "\x1b[0m")
+	default:
+		add(" \x1b[32m" + clip(m.status, width-2) + "\x1b[0m")
+	}
+	add(" \x1b[2mr 15 Enter: add a score · Ctrl+Z: undo · Esc: clear/quit · Ctrl+C: quit\x1b[0m")
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+	for i, l := range lines {
+		sb.WriteString(l + "\x1b[K")
+		if i < len(lines)-1 {
+			sb.WriteString("\r\n")
+		}
+	}
+	sb.WriteString("\x1b[J")
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// shownGames picks up to n games for the table, keeping the selected one
+// in view.
+func shownGames(games []string, sel, n int) []string {
+	if n >= len(games) {
+		return games
+	}
+	if n == 0 {
+		return nil
+	}
+	start := min(max(sel-n+1, 0), len(games)-n)
+	return games[start : start+n]
+}
+
+// swatch is a block in the team color, using 24-bit color.
+func swatch(hex string) string {
+	r, g, b, ok := parseHex(hex)
+	if !ok {
+		return "  "
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm██\x1b[0m", r, g, b)
+}
+
+func parseHex(hex string) (r, g, b int, ok bool) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff), true
+}
+
+func clip(s string, n int) string {
+	if n < 1 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
+
+func pad(s string, n int) string {
+	return s + strings.Repeat(" ", max(n-utf8.RuneCountInString(s), 0))
+}
@@ -0,0 +1,232 @@
+// Package validate checks user input from forms and collects field-level
+// error messages so pages can re-render with the problems highlighted.
+package validate
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits shared by the forms.
+const (
+	MaxBoardName  = 60
+	MaxTeamName   = 40
+	MaxGameName   = 40
+	MaxMemberName = 40
+	MaxMembers    = 20
+	MaxRoundName  = 20
+	MaxUsername   = 32
+	MaxLocation   = 60
+	MaxRoundLimit = 50
+	MaxPlacements = 20
+	MaxPoints     = 1000
+	MinScore      = -100000
+	MaxScore      = 100000
+)
+
+// nameSymbols are the punctuation marks allowed in names besides letters,
+// digits and spaces. Slashes are left out on purpose: names end up in URL
+// paths. Marks like # and & are fine as long as every URL built from a name
+// escapes it with url.PathEscape.
+const nameSymbols = "-_'.&!#()+:"
+
+// Errors maps a form field name to its error message.
+type Errors map[string]string
+
+// Add records msg for field, keeping the first error if there already is one.
+func (e Errors) Add(field, msg string) {
+	if _, ok := e[field]; !ok {
+		e[field] = msg
+	}
+}
+
+// Get returns the error for field, or "".
+func (e Errors) Get(field string) string {
+	return e[field]
+}
+
+// Has reports whether field has an error.
+func (e Errors) Has(field string) bool {
+	_, ok := e[field]
+	return ok
+}
+
+// Any reports whether there are errors at all.
+func (e Errors) Any() bool {
+	return len(e) > 0
+}
+
+// Name checks a required name: not blank, at most max characters and only
+// letters, digits, spaces and a few symbols.
+func (e Errors) Name(field, value string, max int) {
+	if strings.TrimSpace(value) == "" {
+		e.Add(field, "required")
+		return
+	}
+	e.OptionalName(field, value, max)
+}
+
+// OptionalName is like Name but allows an empty value.
+func (e Errors) OptionalName(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		e.Add(field, "must be at most "+strconv.Itoa(max)+" characters")
+		return
+	}
+	for _, r := range value {
+		if !allowedNameRune(r) {
+			e.Add(field, "may only contain letters, numbers, spaces and "+nameSymbols)
+			return
+		}
+	}
+}
+
+// OptionalText checks free text (like a location): at most max characters
+// and no control characters.
+func (e Errors) OptionalText(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		e.Add(field, "must be at most "+strconv.Itoa(max)+" characters")
+		return
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			e.Add(field, "must be a single line of text")
+			return
+		}
+	}
+}
+
+func allowedNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || strings.ContainsRune(nameSymbols, r)
+}
+
+// Int parses a required whole number within [min, max].
+func (e Errors) Int(field, raw string, min, max int) (int, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		e.Add(field, "required")
+		return 0, false
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		e.Add(field, "must be a whole number")
+		return 0, false
+	}
+	if v < min || v > max {
+		e.Add(field, "must be between "+strconv.Itoa(min)+" and "+strconv.Itoa(max))
+		return 0, false
+	}
+	return v, true
+}
+
+// Score parses a round score.
+func (e Errors) Score(field, raw string) (int, bool) {
+	return e.Int(field, raw, MinScore, MaxScore)
+}
+
+// Color checks a "#RRGGBB" hex color.
+func (e Errors) Color(field, raw string) {
+	ok := len(raw) == 7 && raw[0] == '#'
+	for _, r := range raw[min(len(raw), 1):] {
+		ok = ok && strings.ContainsRune("0123456789abcdefABCDEF", r)
+	}
+	if !ok {
+		e.Add(field, "must be a hex color like #1D03AF")
+	}
+}
+
+// DateTime parses an optional local date/time in the given layout.
+// An empty value returns the zero time.
+func (e Errors) DateTime(field, raw, layout string) time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(layout, raw, time.Local)
+	if err != nil {
+		e.Add(field, "must be a date and time")
+		return time.Time{}
+	}
+	return t
+}
+
+// Members splits a comma-separated member list and checks each name.
+func (e Errors) Members(field, raw string) []string {
+	var members []string
+	for _, p := range strings.Split(raw, ",") {
+		name := strings.TrimSpace(p)
+		if name == "" {
+			continue
+		}
+		e.OptionalName(field, name, MaxMemberName)
+		members = append(members, name)
+	}
+	if len(members) > MaxMembers {
+		e.Add(field, "at most "+strconv.Itoa(MaxMembers)+" members")
+	}
+	return members
+}
+
+// Points parses a comma-separated points-per-placement list, first place
+// first (e.g. "10, 6, 4, 2").
+func (e Errors) Points(field, raw string) []int {
+	var points []int
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 || v > MaxPoints {
+			e.Add(field, "must be whole numbers between 0 and "+strconv.Itoa(MaxPoints))
+			return nil
+		}
+		points = append(points, v)
+	}
+	if len(points) == 0 {
+		e.Add(field, "required")
+	}
+	if len(points) > MaxPlacements {
+		e.Add(field, "at most "+strconv.Itoa(MaxPlacements)+" placements")
+	}
+	return points
+}
+
+// Aliases parses one "old name = team name" pair per line, used to match
+// a team that played under different names.
+func (e Errors) Aliases(field, raw string) map[string]string {
+	aliases := make(map[string]string)
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		alias, team, ok := strings.Cut(line, "=")
+		alias, team = strings.TrimSpace(alias), strings.TrimSpace(team)
+		if !ok || alias == "" || team == "" {
+			e.Add(field, "use one \"old name = team name\" per line")
+			return nil
+		}
+		e.OptionalName(field, alias, MaxTeamName)
+		e.OptionalName(field, team, MaxTeamName)
+		aliases[alias] = team
+	}
+	return aliases
+}
+
+// Unique flags field if value matches (case-insensitively) any of taken.
+func (e Errors) Unique(field, value string, taken []string) {
+	for _, t := range taken {
+		if SameName(t, value) {
+			e.Add(field, "\""+value+"\" is already used")
+			return
+		}
+	}
+}
+
+// SameName compares two names the way duplicates are detected.
+func SameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
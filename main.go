@@ -1,18 +1,50 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"io/fs"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/mrjxtr-dev/score-board/internal/auth"
+	"github.com/mrjxtr-dev/score-board/internal/certs"
+	"github.com/mrjxtr-dev/score-board/internal/cli"
 	"github.com/mrjxtr-dev/score-board/internal/config"
+	"github.com/mrjxtr-dev/score-board/internal/handlers"
+	"github.com/mrjxtr-dev/score-board/internal/live"
 	"github.com/mrjxtr-dev/score-board/internal/routes"
 	"github.com/mrjxtr-dev/score-board/internal/store"
 )
 
 func main() {
-	db := store.LoadDB()
-	cfg := config.LoadConfig()
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr, serve))
+}
+
+// serve starts the web server and runs it until SIGINT or SIGTERM, then
+// shuts it down cleanly.
+func serve(cfg *config.Config) error {
+	// Hold the data directory while running, so CLI edits can't be
+	// silently overwritten by the board kept in memory
+	lock, err := store.LockDataDir(cfg.DataDir)
+	if errors.Is(err, store.ErrLocked) {
+		return fmt.Errorf("%w; is another server running on it?", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	db := store.LoadDB(cfg.DataDir)
+	am := auth.NewManager(auth.LoadUsers(cfg.DataDir), auth.LoadTeamTokens(cfg.DataDir))
+	am.SessionTTL = cfg.Auth.SessionTTL
+	lv := live.NewBroker()
+	h := handlers.NewHandlers(cfg, db, am, lv)
 
 	// Mount embedded static filesystem if available (from assets.go)
 	var staticFS http.FileSystem
@@ -20,14 +52,97 @@ func main() {
 		staticFS = http.FS(sub)
 	}
 
-	r := routes.SetupRoutes(cfg, db, staticFS)
+	if cfg.TLS.Auto {
+		dir := certs.Dir(cfg.DataDir)
+		certFile, keyFile, err := certs.Ensure(dir, append(certs.Hosts(), cfg.TLS.Hosts...))
+		if err != nil {
+			return err
+		}
+		cfg.TLS.CertFile, cfg.TLS.KeyFile = certFile, keyFile
+		log.Printf("Serving HTTPS with a certificate from the local CA in %s", dir)
+		log.Printf("Trust it on other devices by installing %s/ca.pem", cfg.LocalURL())
+	}
+
+	r := routes.SetupRoutes(cfg, h, am, lv, staticFS)
 
-	server := &http.Server{
-		Addr:    ":" + cfg.PORT,
-		Handler: r,
+	server := newServer(cfg.Addr, r)
+	servers := []*http.Server{server}
+	if cfg.TLS.Enabled() {
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if cfg.TLS.Redirect != "" {
+		servers = append(servers, newServer(cfg.TLS.Redirect, routes.RedirectHTTPS(cfg)))
 	}
+	// Live streams never finish on their own; end them so Shutdown can
+	server.RegisterOnShutdown(lv.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Println("Starting server on port " + cfg.PORT)
-	log.Printf("Test connection at http://localhost:%s/ping", cfg.PORT)
-	server.ListenAndServe()
+	if cfg.File != "" {
+		log.Println("Using config file " + cfg.File)
+	}
+	log.Println("Starting server on " + cfg.Addr + ", data in " + cfg.DataDir)
+	log.Printf("Test connection at %s/ping", cfg.LocalURL())
+	errc := make(chan error, len(servers))
+	go func() {
+		if cfg.TLS.Enabled() {
+			errc <- server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			errc <- server.ListenAndServe()
+		}
+	}()
+	if len(servers) > 1 {
+		log.Println("Redirecting plain HTTP on " + cfg.TLS.Redirect + " to HTTPS")
+		go func() { errc <- servers[1].ListenAndServe() }()
+	}
+
+	select {
+	case err = <-errc:
+		// A listener failed; stop the others too
+		for _, s := range servers {
+			_ = s.Close()
+		}
+	case <-ctx.Done():
+		// A second Ctrl+C stops right away
+		stop()
+		err = shutdown(servers, cfg.ShutdownTimeout)
+	}
+	// Whatever stopped the server, don't lose what's in memory
+	if ferr := h.Flush(); ferr != nil {
+		return errors.Join(err, fmt.Errorf("saving the board: %w", ferr))
+	}
+	log.Println("Board saved; stopped")
+	return err
+}
+
+// newServer makes a server for addr. Slow or stalled clients can't hold a
+// connection open by dribbling their headers in; there's no write timeout,
+// since live updates stream for as long as a page is open.
+func newServer(addr string, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
+
+// shutdown stops the servers, giving requests up to timeout to finish
+// before they're cut off.
+func shutdown(servers []*http.Server, timeout time.Duration) error {
+	log.Printf("Shutting down; waiting up to %s for requests to finish", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var err error
+	for _, s := range servers {
+		if serr := s.Shutdown(ctx); errors.Is(serr, context.DeadlineExceeded) {
+			log.Println("Requests still running after the timeout; closing them")
+			_ = s.Close()
+			err = fmt.Errorf("shutdown: requests didn't finish within %s", timeout)
+		} else if serr != nil {
+			err = serr
+		}
+	}
+	return err
 }
@@ -0,0 +1,152 @@
+// Animates live changes on the /board page. The server sends per-team
+// deltas ("scores" events); we count totals up, flash a "+15" badge and
+// slide the cards into their new rank order.
+(function () {
+  var grid = document.getElementById("board-cards");
+  if (!grid || !window.EventSource) return;
+
+  var COUNT_MS = 900;
+  var MOVE_MS = 600;
+  var SCHEDULE_MS = 30000;
+
+  function cardFor(team) {
+    var cards = grid.querySelectorAll(".board-card");
+    for (var i = 0; i < cards.length; i++) {
+      if (cards[i].dataset.team === team) return cards[i];
+    }
+    return null;
+  }
+
+  function countUp(el, from, to) {
+    var start = performance.now();
+    el.dataset.total = to;
+    function step(now) {
+      var t = Math.min((now - start) / COUNT_MS, 1);
+      var eased = 1 - Math.pow(1 - t, 3);
+      el.textContent = Math.round(from + (to - from) * eased);
+      if (t < 1) requestAnimationFrame(step);
+    }
+    requestAnimationFrame(step);
+  }
+
+  function flashBadge(card, delta) {
+    if (!delta) return;
+    var badge = document.createElement("span");
+    badge.textContent = (delta > 0 ? "+" : "") + delta;
+    badge.style.cssText =
+      "position:absolute;top:16px;right:20px;padding:6px 16px;border-radius:9999px;" +
+      "font-size:32px;font-weight:900;transition:opacity .6s ease,transform .6s ease;" +
+      (delta > 0 ? "background:#facc15;color:#000;" : "background:#ef4444;color:#fff;");
+    card.appendChild(badge);
+    setTimeout(function () {
+      badge.style.opacity = "0";
+      badge.style.transform = "translateY(-20px)";
+    }, 1600);
+    setTimeout(function () {
+      badge.remove();
+    }, 2300);
+  }
+
+  // FLIP: remember where every card is, reorder the DOM, then animate each
+  // card from its old spot to the new one.
+  function reorder(ranks) {
+    var cards = Array.prototype.slice.call(grid.querySelectorAll(".board-card"));
+    var before = new Map();
+    cards.forEach(function (c) {
+      before.set(c, c.getBoundingClientRect());
+    });
+    cards.sort(function (a, b) {
+      return (ranks[a.dataset.team] || 0) - (ranks[b.dataset.team] || 0);
+    });
+    cards.forEach(function (c) {
+      grid.appendChild(c);
+    });
+    cards.forEach(function (c) {
+      var old = before.get(c);
+      var now = c.getBoundingClientRect();
+      var dx = old.left - now.left;
+      var dy = old.top - now.top;
+      if (!dx && !dy) return;
+      c.style.transition = "none";
+      c.style.transform = "translate(" + dx + "px," + dy + "px)";
+      requestAnimationFrame(function () {
+        requestAnimationFrame(function () {
+          c.style.transition = "transform " + MOVE_MS + "ms ease";
+          c.style.transform = "";
+        });
+      });
+    });
+  }
+
+  function currentRanks() {
+    // Current on-screen order, used to keep ties where they are.
+    var ranks = {};
+    grid.querySelectorAll(".board-card").forEach(function (c, i) {
+      ranks[c.dataset.team] = i + 1;
+    });
+    return ranks;
+  }
+
+  function apply(deltas) {
+    var ranks = currentRanks();
+    var reranked = false;
+    deltas.forEach(function (d) {
+      var card = cardFor(d.team);
+      if (!card) return;
+      var total = card.querySelector("[data-total]");
+      if (total) countUp(total, d.prev_total, d.total);
+      flashBadge(card, d.delta);
+      if (d.rank !== d.prev_rank) reranked = true;
+    });
+    if (!reranked) return;
+    // Sort by the new totals; ties keep their current order.
+    var totals = {};
+    grid.querySelectorAll(".board-card").forEach(function (c) {
+      totals[c.dataset.team] = parseInt(c.querySelector("[data-total]").dataset.total, 10) || 0;
+    });
+    var order = Object.keys(ranks).sort(function (a, b) {
+      return totals[b] - totals[a] || ranks[a] - ranks[b];
+    });
+    var next = {};
+    order.forEach(function (team, i) {
+      next[team] = i + 1;
+    });
+    reorder(next);
+  }
+
+  // "Now playing / up next" moves with the server clock, not with board
+  // changes, so just refresh it every so often.
+  function refreshSchedule() {
+    var banner = document.getElementById("now-playing");
+    if (!banner) return;
+    fetch("/schedule/now", { cache: "no-store" })
+      .then(function (res) {
+        if (!res.ok) throw new Error(res.status);
+        return res.text();
+      })
+      .then(function (html) {
+        banner.innerHTML = html;
+      })
+      .catch(function () {});
+  }
+  setInterval(refreshSchedule, SCHEDULE_MS);
+
+  // The progression chart is a server-rendered SVG; reload it after changes.
+  function refreshChart() {
+    var img = document.getElementById("progress-chart");
+    if (img) img.src = "/board/chart.svg?t=" + Date.now();
+  }
+
+  var source = new EventSource("/events");
+  source.addEventListener("scores", function (e) {
+    try {
+      apply(JSON.parse(e.data));
+    } catch (err) {}
+  });
+  source.addEventListener("board", refreshSchedule);
+  source.addEventListener("board", refreshChart);
+  // Teams were added, removed or renamed: deltas can't describe that.
+  source.addEventListener("teams", function () {
+    location.reload();
+  });
+})();
@@ -0,0 +1,45 @@
+// Drives the /display kiosk page: rotates panels and refreshes them when the
+// server reports a board change.
+(function () {
+  var body = document.body;
+  var root = document.getElementById("display-root");
+  var interval = parseInt(body.dataset.interval, 10) || 15000;
+  var query = body.dataset.query ? "?" + body.dataset.query : "";
+  var current = 0;
+
+  function panels() {
+    return root.querySelectorAll(".display-panel");
+  }
+
+  function show(i) {
+    var list = panels();
+    if (list.length === 0) return;
+    current = i % list.length;
+    list.forEach(function (p, idx) {
+      p.classList.toggle("is-active", idx === current);
+    });
+  }
+
+  function refresh() {
+    fetch("/display/panels" + query, { cache: "no-store" })
+      .then(function (res) {
+        if (!res.ok) throw new Error(res.status);
+        return res.text();
+      })
+      .then(function (html) {
+        root.innerHTML = html;
+        show(current);
+      })
+      .catch(function () {});
+  }
+
+  show(0);
+  setInterval(function () {
+    if (panels().length > 1) show(current + 1);
+  }, interval);
+
+  var source = new EventSource("/events");
+  source.addEventListener("board", refresh);
+  // After a dropped connection the board may have changed while we were away.
+  source.addEventListener("open", refresh);
+})();
@@ -0,0 +1,189 @@
+// Lets scorekeepers keep adding scores with no signal. The service worker
+// keeps pages and static files around; this script queues "Add score"
+// submits made while offline, or that fail to get through (venue Wi-Fi
+// often claims to be online when it isn't), and syncs them to the team's
+// sync endpoint once we're back. Each entry carries its own ID so a
+// retried sync never counts a score twice.
+(function () {
+  if (window.__offline) return;
+  window.__offline = true;
+
+  if ("serviceWorker" in navigator) {
+    navigator.serviceWorker.register("/sw.js").catch(function () {});
+  }
+
+  var KEY = "sb-sync-queue";
+  var FLUSH_MS = 15000;
+  var status = document.getElementById("sync-status");
+  var flushing = false;
+  var problems = [];
+
+  function load() {
+    try {
+      return JSON.parse(localStorage.getItem(KEY)) || [];
+    } catch (e) {
+      return [];
+    }
+  }
+
+  function store(queue) {
+    localStorage.setItem(KEY, JSON.stringify(queue));
+    render();
+  }
+
+  function newID() {
+    if (window.crypto && crypto.randomUUID) return crypto.randomUUID();
+    return Date.now().toString(36) + Math.random().toString(36).slice(2);
+  }
+
+  function csrfToken() {
+    var el = document.querySelector('input[name="csrf_token"]');
+    return el ? el.value : "";
+  }
+
+  function render() {
+    if (!status) return;
+    var pending = load().length;
+    var lines = [];
+    if (pending > 0) {
+      lines.push(
+        pending +
+          (pending === 1 ? " score" : " scores") +
+          " waiting to sync" +
+          (navigator.onLine ? "…" : " (offline)"),
+      );
+    }
+    problems.forEach(function (p) {
+      lines.push(p);
+    });
+    status.textContent = lines.join("\n");
+    status.style.display = lines.length ? "block" : "none";
+  }
+
+  function describe(res) {
+    var what = res.game + (res.round ? " round " + res.round : "") + " (" + res.score + ")";
+    if (res.status === "conflict") {
+      return "Not synced: " + what + " — already saved as " + res.existing;
+    }
+    return "Not synced: " + what + " — " + (res.error || "rejected");
+  }
+
+  function flush() {
+    var queue = load();
+    if (flushing || !queue.length) return;
+    flushing = true;
+
+    // One request per team, in the order the scores were entered
+    var teams = [];
+    queue.forEach(function (e) {
+      if (teams.indexOf(e.team) < 0) teams.push(e.team);
+    });
+
+    var applied = false;
+    var sendNext = function () {
+      if (!teams.length) {
+        flushing = false;
+        render();
+        if (applied) location.reload();
+        return;
+      }
+      var team = teams.shift();
+      var entries = load().filter(function (e) {
+        return e.team === team;
+      });
+      fetch("/board/team/" + encodeURIComponent(team) + "/sync", {
+        method: "POST",
+        credentials: "same-origin",
+        headers: {
+          "Content-Type": "application/json",
+          "X-CSRF-Token": csrfToken(),
+        },
+        body: JSON.stringify({
+          entries: entries.map(function (e) {
+            return { id: e.id, game: e.game, round: e.round, score: e.score };
+          }),
+        }),
+      })
+        .then(function (res) {
+          if (!res.ok) throw new Error(res.status);
+          return res.json();
+        })
+        .then(function (body) {
+          var done = {};
+          (body.results || []).forEach(function (res) {
+            done[res.id] = true;
+            if (res.status === "applied") applied = true;
+            else problems.push(describe(res));
+          });
+          store(
+            load().filter(function (e) {
+              return !done[e.id];
+            }),
+          );
+          sendNext();
+        })
+        .catch(function () {
+          // Still offline or the server is unhappy; try again later
+          flushing = false;
+          render();
+        });
+    };
+    sendNext();
+  }
+
+  // nextRound is the round a score from form fills: the one after the
+  // rounds the page shows and any already queued for the same game. Sending
+  // it, rather than asking for "the next round", lets the server tell when
+  // another device filled that round in the meantime.
+  function nextRound(form, queue) {
+    var n = parseInt(form.dataset.round, 10) || 1;
+    queue.forEach(function (e) {
+      var r = parseInt(e.round, 10);
+      if (e.team === form.dataset.team && e.game === form.dataset.game && r >= n) n = r + 1;
+    });
+    return String(n);
+  }
+
+  // enqueue saves the form's score for the next sync, reporting whether
+  // there was one to save.
+  function enqueue(form) {
+    if (!form || !form.hasAttribute("data-offline")) return false;
+    var score = form.querySelector('input[name="score"]');
+    if (!score || score.value === "" || isNaN(parseInt(score.value, 10))) return false;
+    var queue = load();
+    queue.push({
+      id: newID(),
+      team: form.dataset.team,
+      game: form.dataset.game,
+      round: nextRound(form, queue),
+      score: parseInt(score.value, 10),
+      at: Date.now(),
+    });
+    store(queue);
+    score.value = "";
+    return true;
+  }
+
+  // Known to be offline: don't even try
+  function queueSubmit(e) {
+    if (navigator.onLine || !enqueue(e.target)) return;
+    e.preventDefault();
+    e.stopPropagation();
+  }
+
+  // Tried and the request never got an answer
+  function queueFailed(e) {
+    if (enqueue(e.detail.elt)) flush();
+  }
+
+  // Capture phase, so htmx never sees a submit we queued
+  document.addEventListener("submit", queueSubmit, true);
+  document.addEventListener("htmx:sendError", queueFailed);
+  document.addEventListener("htmx:timeout", queueFailed);
+
+  window.addEventListener("online", flush);
+  window.addEventListener("offline", render);
+  setInterval(flush, FLUSH_MS);
+  render();
+  flush();
+})();
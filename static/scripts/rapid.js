@@ -0,0 +1,218 @@
+// Keyboard score entry. Each line ("r 15") is logged right away and sent
+// with whatever else was typed in the next moment, as one all-or-nothing
+// batch. Ctrl+Z (or Undo) takes back the last entry: straight out of the
+// queue if it hasn't gone yet, otherwise with an undo sent to the server.
+(function () {
+  var root = document.getElementById("rapid");
+  if (!root) return;
+
+  var SEND_MS = 600;
+  var RETRY_MS = 3000;
+
+  var input = document.getElementById("rapid-input");
+  var gameSelect = document.getElementById("rapid-game");
+  var scoring = document.getElementById("rapid-scoring");
+  var message = document.getElementById("rapid-message");
+  var logList = document.getElementById("rapid-log");
+  var csrf = root.querySelector('input[name="csrf_token"]').value;
+
+  var teams = {};
+  root.querySelectorAll("#rapid-keys [data-key]").forEach(function (el) {
+    teams[el.dataset.key] = el.dataset.team;
+  });
+
+  var log = []; // every entry typed, newest last
+  var queue = []; // entries and undos waiting to be sent
+  var inflight = null; // the batch on the wire: {key, items}
+  var timer = null;
+
+  function newKey() {
+    if (window.crypto && crypto.randomUUID) return crypto.randomUUID();
+    return Date.now().toString(36) + Math.random().toString(36).slice(2);
+  }
+
+  function say(text) {
+    message.textContent = text || "";
+  }
+
+  function render() {
+    logList.innerHTML = "";
+    for (var i = log.length - 1; i >= 0; i--) {
+      var e = log[i];
+      var li = document.createElement("li");
+      li.className = "p-2";
+      li.style.cssText =
+        "display:flex;justify-content:space-between;gap:12px;border-radius:8px;" +
+        "background:rgba(255,255,255,.04);border:1px solid rgba(255,255,255,.12);";
+      if (e.state === "undone" || e.state === "failed") li.style.opacity = ".5";
+      var what = document.createElement("span");
+      what.textContent =
+        e.team + " · " + e.game + (e.round ? " round " + e.round : "") + ": " + e.score;
+      if (e.state === "undone") what.style.textDecoration = "line-through";
+      var state = document.createElement("span");
+      state.className = "text-sm";
+      state.textContent =
+        e.state === "failed" ? e.error : e.state === "undoing" ? "undoing…" : e.state;
+      li.appendChild(what);
+      li.appendChild(state);
+      logList.appendChild(li);
+    }
+  }
+
+  function schedule(ms) {
+    clearTimeout(timer);
+    timer = setTimeout(send, ms);
+  }
+
+  function send() {
+    if (inflight || !queue.length) return;
+    inflight = { key: newKey(), items: queue };
+    queue = [];
+    inflight.items.forEach(function (it) {
+      if (!it.undo) it.entry.state = "sending";
+    });
+    render();
+    post(inflight);
+  }
+
+  function post(batch) {
+    fetch("/rapid/batch", {
+      method: "POST",
+      credentials: "same-origin",
+      headers: {
+        "Content-Type": "application/json",
+        "X-CSRF-Token": csrf,
+        "Idempotency-Key": batch.key,
+      },
+      body: JSON.stringify({
+        entries: batch.items.map(function (it) {
+          var e = it.entry;
+          return it.undo
+            ? { team: e.team, game: e.game, round: e.round, score: e.score, undo: true }
+            : { team: e.team, game: e.game, score: e.score };
+        }),
+      }),
+    })
+      .then(function (res) {
+        if (res.status !== 200 && res.status !== 400) throw new Error(res.status);
+        return res.json().then(function (body) {
+          done(batch, body);
+        });
+      })
+      .catch(function () {
+        // Same key on retry, so a batch that did land isn't applied twice
+        say("Can't reach the server; retrying…");
+        setTimeout(function () {
+          post(batch);
+        }, RETRY_MS);
+      });
+  }
+
+  function done(batch, body) {
+    if (body.errors && body.errors.length) {
+      // Nothing in the batch was saved
+      var errs = {};
+      body.errors.forEach(function (e) {
+        errs[e.index] = e.error;
+      });
+      batch.items.forEach(function (it, i) {
+        if (it.undo) {
+          it.entry.state = "saved";
+        } else {
+          it.entry.state = "failed";
+          it.entry.error = errs[i] || "not saved";
+        }
+      });
+      say(body.errors[0].error);
+    } else {
+      batch.items.forEach(function (it, i) {
+        if (it.undo) {
+          it.entry.state = "undone";
+        } else {
+          it.entry.state = "saved";
+          it.entry.round = body.results[i].round;
+        }
+      });
+      say("");
+    }
+    inflight = null;
+    render();
+    if (queue.length) schedule(0);
+  }
+
+  function add(line) {
+    var m = /^\s*([a-z])\s*(-?\d+)\s*$/i.exec(line);
+    if (!m) {
+      say('Type a team key and a score, like "r 15".');
+      return false;
+    }
+    var team = teams[m[1].toLowerCase()];
+    if (!team) {
+      say('No team on key "' + m[1] + '".');
+      return false;
+    }
+    var entry = {
+      team: team,
+      game: gameSelect.value,
+      score: parseInt(m[2], 10),
+      round: "",
+      state: "queued",
+    };
+    log.push(entry);
+    queue.push({ entry: entry });
+    say("");
+    render();
+    schedule(SEND_MS);
+    return true;
+  }
+
+  function undo() {
+    for (var i = log.length - 1; i >= 0; i--) {
+      var e = log[i];
+      if (e.state === "queued") {
+        queue = queue.filter(function (it) {
+          return it.entry !== e;
+        });
+        e.state = "undone";
+        render();
+        return;
+      }
+      if (e.state === "saved") {
+        e.state = "undoing";
+        queue.push({ entry: e, undo: true });
+        render();
+        schedule(0);
+        return;
+      }
+      if (e.state === "sending" || e.state === "undoing") {
+        say("Still sending; try again in a moment.");
+        return;
+      }
+    }
+  }
+
+  input.addEventListener("keydown", function (ev) {
+    if (ev.key === "Enter") {
+      ev.preventDefault();
+      if (add(input.value)) input.value = "";
+    }
+  });
+
+  document.addEventListener("keydown", function (ev) {
+    if ((ev.ctrlKey || ev.metaKey) && ev.key.toLowerCase() === "z") {
+      ev.preventDefault();
+      undo();
+    }
+  });
+
+  document.getElementById("rapid-undo").addEventListener("click", function () {
+    undo();
+    input.focus();
+  });
+
+  gameSelect.addEventListener("change", function () {
+    var opt = gameSelect.options[gameSelect.selectedIndex];
+    scoring.textContent = opt.dataset.label || "";
+    input.focus();
+  });
+})();
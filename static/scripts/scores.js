@@ -0,0 +1,22 @@
+// In-place score edits on the team page. htmx posts the forms and swaps the
+// game card it gets back; without JavaScript the same forms post normally
+// and redirect. Every edit carries the board version the page was last
+// brought up to date with, so a stale page still gets turned away.
+(function () {
+  if (!window.htmx || window.__scores) return;
+  window.__scores = true;
+
+  // Validation errors (400) come back as a card with the messages in it,
+  // and a stale page (409) as the conflict page; both should be shown.
+  htmx.config.responseHandling = [
+    { code: "204", swap: false },
+    { code: "[23]..", swap: true },
+    { code: "^(400|409)$", swap: true, error: false },
+    { code: "[45]..", swap: false, error: true },
+  ];
+
+  document.body.addEventListener("htmx:configRequest", function (e) {
+    var version = document.getElementById("board-version");
+    if (version) e.detail.parameters.set("version", version.value);
+  });
+})();
@@ -0,0 +1,87 @@
+// Service worker for offline scorekeeping. Pages come from the network
+// when we can reach it and from the cache when we can't; static files are
+// cache-first. Scores posted while offline are queued by offline.js, not
+// here.
+var CACHE = "sb-v4";
+var SHELL = [
+  "/static/css/style.css",
+  "/static/scripts/offline.js",
+  "/static/scripts/htmx.min.js",
+  "/static/scripts/scores.js",
+  "/static/manifest.webmanifest",
+  "/static/icons/icon-192.png",
+  "/static/icons/icon-512.png",
+];
+
+self.addEventListener("install", function (e) {
+  e.waitUntil(
+    caches.open(CACHE).then(function (c) {
+      return c.addAll(SHELL);
+    }),
+  );
+  self.skipWaiting();
+});
+
+self.addEventListener("activate", function (e) {
+  e.waitUntil(
+    caches.keys().then(function (keys) {
+      return Promise.all(
+        keys
+          .filter(function (k) {
+            return k !== CACHE;
+          })
+          .map(function (k) {
+            return caches.delete(k);
+          }),
+      );
+    }),
+  );
+  self.clients.claim();
+});
+
+function put(req, res) {
+  if (res.ok) {
+    var copy = res.clone();
+    caches.open(CACHE).then(function (c) {
+      c.put(req, copy);
+    });
+  }
+  return res;
+}
+
+self.addEventListener("fetch", function (e) {
+  var req = e.request;
+  var url = new URL(req.url);
+  if (req.method !== "GET" || url.origin !== location.origin) return;
+  if (url.pathname === "/events") return;
+
+  if (req.mode === "navigate") {
+    e.respondWith(
+      fetch(req)
+        .then(function (res) {
+          return put(req, res);
+        })
+        .catch(function () {
+          return caches.match(req).then(function (hit) {
+            return hit || caches.match("/board");
+          }).then(function (hit) {
+            return hit || new Response("Offline", { status: 503 });
+          });
+        }),
+    );
+    return;
+  }
+
+  if (url.pathname.indexOf("/static/") === 0) {
+    e.respondWith(
+      caches.match(req).then(function (hit) {
+        return (
+          hit ||
+          fetch(req).then(function (res) {
+            return put(req, res);
+          })
+        );
+      }),
+    );
+  }
+});
@@ -0,0 +1,95 @@
+// Keeps game clocks ticking on the page. The server is the clock everyone
+// follows: each update carries its current time, and we draw countdowns
+// against that (corrected for this device's clock offset).
+(function () {
+  if (window.__timers) return;
+  window.__timers = true;
+
+  var offset = 0; // server clock minus local clock, in ms
+  var timers = {};
+
+  function load(snapshot) {
+    offset = snapshot.server_now - Date.now();
+    timers = {};
+    (snapshot.timers || []).forEach(function (t) {
+      timers[t.game] = t;
+    });
+    renderStrips();
+    tick();
+  }
+
+  function remaining(t) {
+    if (!t.running) return t.remaining_ms;
+    return Math.max(t.ends_at - (Date.now() + offset), 0);
+  }
+
+  function format(ms) {
+    var secs = Math.ceil(ms / 1000);
+    var h = Math.floor(secs / 3600);
+    var m = Math.floor(secs / 60) % 60;
+    var s = secs % 60;
+    var pad = function (v) {
+      return (v < 10 ? "0" : "") + v;
+    };
+    return h > 0 ? h + ":" + pad(m) + ":" + pad(s) : m + ":" + pad(s);
+  }
+
+  function active(t) {
+    return t.running || t.remaining_ms < t.duration_ms;
+  }
+
+  // Rebuild the audience strips so timers that just started show up.
+  function renderStrips() {
+    document.querySelectorAll("[data-timer-strip]").forEach(function (strip) {
+      strip.innerHTML = "";
+      Object.keys(timers).forEach(function (game) {
+        if (!active(timers[game])) return;
+        var chip = document.createElement("div");
+        chip.style.cssText =
+          "display:flex;align-items:baseline;gap:12px;padding:8px 20px;border-radius:9999px;" +
+          "background:rgba(255,255,255,.06);border:1px solid rgba(255,255,255,.16);";
+        var name = document.createElement("span");
+        name.className = "font-bold uppercase";
+        name.textContent = game;
+        var clock = document.createElement("span");
+        clock.className = "font-black";
+        clock.style.fontVariantNumeric = "tabular-nums";
+        clock.dataset.timerClock = game;
+        chip.appendChild(name);
+        chip.appendChild(clock);
+        strip.appendChild(chip);
+      });
+    });
+  }
+
+  function tick() {
+    document.querySelectorAll("[data-timer-clock]").forEach(function (el) {
+      var t = timers[el.dataset.timerClock];
+      if (!t) return;
+      var ms = remaining(t);
+      el.textContent = format(ms);
+      el.style.color = ms === 0 && active(t) ? "#ef4444" : "";
+    });
+  }
+
+  function resync() {
+    fetch("/timers.json", { cache: "no-store" })
+      .then(function (res) {
+        return res.json();
+      })
+      .then(load)
+      .catch(function () {});
+  }
+
+  var initial = document.getElementById("timers-state");
+  if (initial) load(JSON.parse(initial.textContent));
+  setInterval(tick, 250);
+
+  if (window.EventSource) {
+    var source = new EventSource("/events");
+    source.addEventListener("timers", function (e) {
+      load(JSON.parse(e.data));
+    });
+    source.addEventListener("open", resync);
+  }
+})();